@@ -1,21 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	db "github.com/kareemhamed001/faq/internal/DB"
 	"github.com/kareemhamed001/faq/internal/config"
+	"github.com/kareemhamed001/faq/internal/events"
 	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/helpers"
 	"github.com/kareemhamed001/faq/internal/logger"
+	"github.com/kareemhamed001/faq/internal/mailer"
 	"github.com/kareemhamed001/faq/internal/middlewares"
 	"github.com/kareemhamed001/faq/internal/routes"
+	"github.com/kareemhamed001/faq/internal/search"
 	"github.com/kareemhamed001/faq/internal/services"
 )
 
+// shutdownTimeout is how long in-flight requests get to finish on shutdown.
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 
 	config := config.NewConfig()
@@ -32,6 +45,26 @@ func main() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	keys, err := helpers.NewKeySet(config.JWTPrivateKey, config.JWTKeysFile)
+	if err != nil {
+		logr.Errorw("failed to load jwt keys", "error", err)
+		log.Fatal("Failed to load JWT keys:", err)
+	}
+	if config.JWTKeysFile == "" && config.AppEnv == "production" && config.JWTPrivateKey == "your_jwt_private_key" {
+		logr.Warnw("using the default HS256 secret in production; set JWT_PRIVATE_KEY or JWT_KEYS_FILE")
+	}
+	stopKeyReload := make(chan struct{})
+	defer close(stopKeyReload)
+	keys.StartReloading(config.JWTKeysReloadInterval, stopKeyReload, func(err error) {
+		logr.Errorw("failed to reload jwt keys", "error", err)
+	})
+
+	mail, err := mailer.New(config.MailerDriver, config.MailerFrom, config.MailerFileDir, logr)
+	if err != nil {
+		logr.Errorw("failed to configure mailer", "error", err)
+		log.Fatal("Failed to configure mailer:", err)
+	}
+
 	router := gin.Default()
 	router.Use(gin.Recovery())
 
@@ -39,13 +72,28 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://127.0.0.1:5173", "http://localhost:8081"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept-Language"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept-Language", "X-API-Key"},
 		ExposeHeaders:    []string{"Content-Length", "Authorization"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	router.Use(middlewares.SetUserData(config.JWTPrivateKey))
+	settingsService := services.NewSettingsService(db)
+	loginThrottleService := services.NewLoginThrottleService(db, logr, services.LoginThrottleOptions{
+		MaxAccountFailures: config.LoginMaxAccountFailures,
+		MaxIPFailures:      config.LoginMaxIPFailures,
+		Window:             config.LoginFailureWindow,
+		BaseLockout:        config.LoginLockoutBase,
+		MaxLockout:         config.LoginLockoutMax,
+	})
+	authService := services.NewAuthService(db, keys, settingsService, loginThrottleService, services.AuthOptions{
+		AccessTokenTTL:  config.AccessTokenTTL,
+		RefreshTokenTTL: config.RefreshTokenTTL,
+		MFAChallengeTTL: config.MFAChallengeTTL,
+	})
+	apiKeyService := services.NewAPIKeyService(db)
+	router.Use(middlewares.APIKeyAuth(apiKeyService))
+	router.Use(middlewares.SetUserData(authService))
 
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -53,27 +101,165 @@ func main() {
 		})
 	})
 
+	// JWKS Routes
+	jwksHandler := handlers.NewJWKSHandler(keys)
+
+	routes.SetupJWKSRoutes(router, *jwksHandler)
+
 	// Auth Routes
-	authService := services.NewAuthService(db, config.JWTPrivateKey)
-	authHandler := handlers.NewAuthHandler(*authService)
+	verificationService := services.NewEmailVerificationService(db, mail, keys, config.FrontendURL+"/verify-email", config.EmailVerificationTTL, config.VerificationResendWait)
+	authHandler := handlers.NewAuthHandler(*authService, *verificationService)
 
-	routes.SetupAuthRoutes(router, *authHandler)
+	routes.SetupAuthRoutes(router, *authHandler, authService)
+
+	// Profile Routes
+	profileService := services.NewProfileService(db, authService)
+	profileHandler := handlers.NewProfileHandler(*profileService, *verificationService)
+
+	routes.SetupProfileRoutes(router, *profileHandler, authService)
+
+	// MFA Routes
+	mfaService := services.NewMFAService(db, authService, settingsService, config.MFAIssuer)
+	mfaHandler := handlers.NewMFAHandler(*mfaService)
+
+	routes.SetupMFARoutes(router, *mfaHandler, authService)
+
+	// Settings Routes
+	settingsHandler := handlers.NewSettingsHandler(*settingsService)
+
+	routes.SetupSettingsRoutes(router, *settingsHandler, authService)
+
+	// Admin User Routes
+	adminUserService := services.NewAdminUserService(db, authService)
+	adminUserHandler := handlers.NewAdminUserHandler(*adminUserService, *loginThrottleService)
+
+	routes.SetupAdminUserRoutes(router, *adminUserHandler, authService)
+
+	// Search Index
+	searchIndex, err := services.OpenSearchIndex(config.SearchBackend, db, config.SearchIndexPath)
+	if err != nil {
+		logr.Errorw("failed to open search index", "backend", config.SearchBackend, "error", err)
+		log.Fatal("Failed to open search index:", err)
+	}
+	faqIndexer := services.NewFAQIndexer(db, searchIndex, func(err error) {
+		logr.Errorw("failed to update search index", "error", err)
+	})
+	stopIndexSave := make(chan struct{})
+	var indexSaved <-chan struct{}
+	if local, ok := searchIndex.(*search.LocalIndex); ok {
+		if local.Len() == 0 {
+			indexed, err := faqIndexer.Rebuild(context.Background())
+			if err != nil {
+				logr.Errorw("failed to build search index", "error", err)
+				log.Fatal("Failed to build search index:", err)
+			}
+			logr.Infow("built search index", "faqs", indexed)
+		} else {
+			// The saved index may miss changes made after its last save.
+			changed, err := faqIndexer.Reconcile(context.Background())
+			if err != nil {
+				logr.Errorw("failed to reconcile search index", "error", err)
+				log.Fatal("Failed to reconcile search index:", err)
+			}
+			logr.Infow("reconciled search index", "faqs_changed", changed)
+		}
+		indexSaved = local.StartSaving(config.SearchIndexSaveInterval, stopIndexSave, func(err error) {
+			logr.Errorw("failed to save search index", "error", err)
+		})
+	}
+
+	// Trash Routes
+	trashService := services.NewTrashService(db, config.TrashRetention, faqIndexer)
+	stopTrashPurge := make(chan struct{})
+	defer close(stopTrashPurge)
+	trashService.StartPurging(config.TrashPurgeInterval, stopTrashPurge, func(err error) {
+		logr.Errorw("failed to purge trash", "error", err)
+	})
+	trashHandler := handlers.NewTrashHandler(*trashService)
+
+	routes.SetupTrashRoutes(router, *trashHandler, authService)
+
+	// Password Routes
+	passwordService := services.NewPasswordService(db, authService, mail, logr, config.FrontendURL+"/reset-password", config.PasswordResetTTL)
+	passwordHandler := handlers.NewPasswordHandler(*passwordService)
+
+	routes.SetupPasswordRoutes(router, *passwordHandler, authService)
+
+	// API Key Routes
+	apiKeyHandler := handlers.NewAPIKeyHandler(*apiKeyService)
+
+	routes.SetupAPIKeyRoutes(router, *apiKeyHandler, authService)
 
 	// FAQ Category Routes
 	faqCategoryService := services.NewFAQCategoryService(db)
 	faqCategoryHandler := handlers.NewFAQCategoryHandler(*faqCategoryService)
 
-	routes.SetupFaqCategoriesRoutes(router, *faqCategoryHandler, config.JWTPrivateKey)
+	routes.SetupFaqCategoriesRoutes(router, *faqCategoryHandler, authService)
 
 	// FAQ  Routes
-	faqService := services.NewFAQService(db)
-	storeService := services.NewStoreService(db)
-	faqHandler := handlers.NewFAQHandler(*faqService, *storeService)
-	storeHandler := handlers.NewStoreHandler(*storeService)
+	faqEvents := events.NewBus()
+	faqEvents.Subscribe(func(event events.Event) {
+		logr.Infow("faq event", "type", event.Type, "faq_id", event.FAQID, "store_id", event.StoreID, "occurred_at", event.OccurredAt)
+	})
+	faqScheduler := services.NewFAQScheduler(db, settingsService, faqEvents)
+	stopFAQScheduler := make(chan struct{})
+	defer close(stopFAQScheduler)
+	faqScheduler.Start(config.FAQSchedulerInterval, stopFAQScheduler, func(err error) {
+		logr.Errorw("failed to run faq scheduler", "error", err)
+	})
+
+	faqService := services.NewFAQService(db, config.RequireVerifiedMerchants, faqIndexer)
+	viewCounter := services.NewViewCounter(db, config.ViewMaxPending)
+	stopViewFlush := make(chan struct{})
+	viewsFlushed := viewCounter.Start(config.ViewFlushInterval, stopViewFlush, func(err error) {
+		logr.Errorw("failed to flush faq views", "error", err)
+	})
+
+	storeService := services.NewStoreService(db, faqIndexer, viewCounter)
+	searchAnalyticsService := services.NewSearchAnalyticsService(db)
+	faqHandler := handlers.NewFAQHandler(*faqService, *storeService, *searchAnalyticsService)
+	storeHandler := handlers.NewStoreHandler(*storeService, *searchAnalyticsService)
 
-	routes.SetupFaqRoutes(router, *faqHandler, config.JWTPrivateKey)
+	routes.SetupFaqRoutes(router, *faqHandler, authService)
 	routes.SetupStoreRoutes(router, *storeHandler)
 
-	logr.Infow("starting server", "port", config.AppPort)
-	router.Run(":" + strconv.Itoa(config.AppPort))
+	// Ask Routes
+	askService := services.NewAskService(db, faqIndexer, config.AskMinConfidence)
+	askHandler := handlers.NewAskHandler(*askService, *searchAnalyticsService)
+
+	routes.SetupAskRoutes(router, *askHandler, authService)
+
+	// Search Analytics Routes
+	searchAnalyticsHandler := handlers.NewSearchAnalyticsHandler(*searchAnalyticsService)
+
+	routes.SetupSearchAnalyticsRoutes(router, *searchAnalyticsHandler, authService)
+
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	server := &http.Server{Addr: ":" + strconv.Itoa(config.AppPort), Handler: router}
+	go func() {
+		logr.Infow("starting server", "port", config.AppPort)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logr.Errorw("server failed", "error", err)
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	logr.Infow("shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logr.Errorw("failed to shut down server gracefully", "error", err)
+	}
+
+	// Requests are done; write out what is still buffered in memory.
+	close(stopViewFlush)
+	<-viewsFlushed
+	close(stopIndexSave)
+	if indexSaved != nil {
+		<-indexSaved
+	}
 }
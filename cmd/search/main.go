@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	db "github.com/kareemhamed001/faq/internal/DB"
+	"github.com/kareemhamed001/faq/internal/config"
+	"github.com/kareemhamed001/faq/internal/search"
+	"github.com/kareemhamed001/faq/internal/services"
+)
+
+func main() {
+
+	cfg := config.NewConfig()
+
+	database, err := db.InitializeDB(cfg.DBDriver, cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	if len(os.Args) < 2 {
+		showUsage()
+		os.Exit(0)
+	}
+
+	command := os.Args[1]
+
+	if command == "index:rebuild" {
+		index, err := services.OpenSearchIndex(cfg.SearchBackend, database, cfg.SearchIndexPath)
+		if err != nil {
+			log.Fatal("Error opening search index:", err)
+		}
+
+		fmt.Printf("Rebuilding %s search index...\n", cfg.SearchBackend)
+		indexed, err := services.NewFAQIndexer(database, index, nil).Rebuild(context.Background())
+		if err != nil {
+			log.Fatal("Error rebuilding search index:", err)
+		}
+		if local, ok := index.(*search.LocalIndex); ok {
+			if err := local.Save(); err != nil {
+				log.Fatal("Error saving search index:", err)
+			}
+		}
+		fmt.Printf("Search index rebuilt with %d FAQs!\n", indexed)
+		os.Exit(0)
+	}
+
+	showUsage()
+}
+
+func showUsage() {
+	fmt.Println("\nCommands:")
+	fmt.Println("  index:rebuild    Rebuild the FAQ search index from the database")
+	fmt.Println("\nThe index is chosen by SEARCH_BACKEND (postgres or local); the local index")
+	fmt.Println("is written to SEARCH_INDEX_PATH. The API reconciles the local index with the database")
+	fmt.Println("whenever it starts, so restart it afterwards rather than rebuilding while it runs.")
+	fmt.Println("\nExamples:")
+	fmt.Println("  go run cmd/search/main.go index:rebuild")
+	fmt.Println("  SEARCH_BACKEND=local go run cmd/search/main.go index:rebuild")
+}
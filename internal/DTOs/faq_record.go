@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+// FAQRecord is the portable form of an FAQ read by imports and written by
+// exports. Categories are referenced by their path of names, such as
+// "Shipping > International", so files move between environments.
+// ID only matters on import together with an exported "faq-<id>" key. StoreID
+// places the FAQ in a store when admins import it (merchants always import
+// into their own store), and Status, when set in an admin import, becomes the
+// FAQ's status.
+type FAQRecord struct {
+	ID           uint             `json:"id,omitempty"`
+	ExternalKey  string           `json:"external_key,omitempty"`
+	StoreID      *uint            `json:"store_id,omitempty"`
+	Category     string           `json:"category"`
+	Status       string           `json:"status,omitempty"`
+	PublishAt    *time.Time       `json:"publish_at,omitempty"`
+	ExpireAt     *time.Time       `json:"expire_at,omitempty"`
+	Translations []TranslationDTO `json:"translations"`
+}
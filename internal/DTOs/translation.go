@@ -5,3 +5,8 @@ type TranslationDTO struct {
 	Question string `json:"question"`
 	Answer   string `json:"answer"`
 }
+
+type CategoryTranslationDTO struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+}
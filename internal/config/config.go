@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,6 +19,44 @@ type Config struct {
 	DBPassword    string
 	DBName        string
 	JWTPrivateKey string
+
+	JWTKeysFile           string
+	JWTKeysReloadInterval time.Duration
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	MFAChallengeTTL time.Duration
+	MFAIssuer       string
+
+	LoginMaxAccountFailures int
+	LoginMaxIPFailures      int
+	LoginFailureWindow      time.Duration
+	LoginLockoutBase        time.Duration
+	LoginLockoutMax         time.Duration
+
+	FrontendURL      string
+	MailerDriver     string
+	MailerFrom       string
+	MailerFileDir    string
+	PasswordResetTTL time.Duration
+
+	EmailVerificationTTL     time.Duration
+	VerificationResendWait   time.Duration
+	RequireVerifiedMerchants bool
+
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	FAQSchedulerInterval time.Duration
+
+	SearchBackend           string
+	SearchIndexPath         string
+	SearchIndexSaveInterval time.Duration
+
+	AskMinConfidence float64
+
+	ViewFlushInterval time.Duration
+	ViewMaxPending    int
 }
 
 func NewConfig() *Config {
@@ -35,6 +74,44 @@ func NewConfig() *Config {
 		DBPassword:    getEnvString("DB_PASSWORD", "admin"),
 		DBName:        getEnvString("DB_NAME", "faq_db"),
 		JWTPrivateKey: getEnvString("JWT_PRIVATE_KEY", "your_jwt_private_key"),
+
+		JWTKeysFile:           getEnvString("JWT_KEYS_FILE", ""),
+		JWTKeysReloadInterval: time.Duration(getEnvInt("JWT_KEYS_RELOAD_SECONDS", 300)) * time.Second,
+
+		AccessTokenTTL:  time.Duration(getEnvInt("ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute,
+		RefreshTokenTTL: time.Duration(getEnvInt("REFRESH_TOKEN_TTL_HOURS", 720)) * time.Hour,
+		MFAChallengeTTL: time.Duration(getEnvInt("MFA_CHALLENGE_TTL_MINUTES", 5)) * time.Minute,
+		MFAIssuer:       getEnvString("MFA_ISSUER", "FAQ-MS"),
+
+		LoginMaxAccountFailures: getEnvInt("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+		LoginMaxIPFailures:      getEnvInt("LOGIN_MAX_IP_FAILURES", 20),
+		LoginFailureWindow:      time.Duration(getEnvInt("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute,
+		LoginLockoutBase:        time.Duration(getEnvInt("LOGIN_LOCKOUT_BASE_SECONDS", 30)) * time.Second,
+		LoginLockoutMax:         time.Duration(getEnvInt("LOGIN_LOCKOUT_MAX_MINUTES", 60)) * time.Minute,
+
+		FrontendURL:      getEnvString("FRONTEND_URL", "http://localhost:5173"),
+		MailerDriver:     getEnvString("MAILER_DRIVER", "log"),
+		MailerFrom:       getEnvString("MAILER_FROM", "no-reply@faq.local"),
+		MailerFileDir:    getEnvString("MAILER_FILE_DIR", "./tmp/mail"),
+		PasswordResetTTL: time.Duration(getEnvInt("PASSWORD_RESET_TTL_MINUTES", 60)) * time.Minute,
+
+		EmailVerificationTTL:     time.Duration(getEnvInt("EMAIL_VERIFICATION_TTL_HOURS", 48)) * time.Hour,
+		VerificationResendWait:   time.Duration(getEnvInt("EMAIL_VERIFICATION_RESEND_SECONDS", 60)) * time.Second,
+		RequireVerifiedMerchants: getEnvBool("REQUIRE_VERIFIED_MERCHANTS", false),
+
+		TrashRetention:     time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		TrashPurgeInterval: time.Duration(getEnvInt("TRASH_PURGE_INTERVAL_MINUTES", 60)) * time.Minute,
+
+		FAQSchedulerInterval: time.Duration(getEnvInt("FAQ_SCHEDULER_INTERVAL_SECONDS", 60)) * time.Second,
+
+		SearchBackend:           getEnvString("SEARCH_BACKEND", "postgres"),
+		SearchIndexPath:         getEnvString("SEARCH_INDEX_PATH", "./tmp/search/faqs.idx"),
+		SearchIndexSaveInterval: time.Duration(getEnvInt("SEARCH_INDEX_SAVE_SECONDS", 30)) * time.Second,
+
+		AskMinConfidence: float64(getEnvInt("ASK_MIN_CONFIDENCE_PERCENT", 50)) / 100,
+
+		ViewFlushInterval: time.Duration(getEnvInt("VIEW_FLUSH_SECONDS", 10)) * time.Second,
+		ViewMaxPending:    getEnvInt("VIEW_MAX_PENDING", 10000),
 	}
 }
 
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Type identifies what happened.
+type Type string
+
+const (
+	// FAQPublished fires when a scheduled FAQ's publish_at passes and it becomes visible.
+	FAQPublished Type = "faq.published"
+	// FAQExpired fires when an FAQ's expire_at passes and it is hidden again.
+	FAQExpired Type = "faq.expired"
+)
+
+// Event describes a change to an FAQ. StoreID is nil for global FAQs.
+type Event struct {
+	Type       Type      `json:"type"`
+	FAQID      uint      `json:"faq_id"`
+	StoreID    *uint     `json:"store_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Handler reacts to an event. Handlers run synchronously in the publisher's
+// goroutine and should hand slow work off elsewhere.
+type Handler func(Event)
+
+// Bus fans events out to every subscribed handler. It is safe for concurrent use.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/requests"
+	"github.com/kareemhamed001/faq/internal/services"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+type AdminUserHandler struct {
+	adminUserService     *services.AdminUserService
+	loginThrottleService *services.LoginThrottleService
+}
+
+func NewAdminUserHandler(adminUserService services.AdminUserService, loginThrottleService services.LoginThrottleService) *AdminUserHandler {
+	return &AdminUserHandler{
+		adminUserService:     &adminUserService,
+		loginThrottleService: &loginThrottleService,
+	}
+}
+
+func (h *AdminUserHandler) ListUsers(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	sortDir := ctx.DefaultQuery("sort", "desc")
+
+	filter := services.UserFilter{
+		Role:   types.UserRole(ctx.Query("role")),
+		Email:  ctx.Query("email"),
+		Status: ctx.Query("status"),
+	}
+
+	users, total, err := h.adminUserService.ListUsers(ctx.Request.Context(), filter, page, pageSize, sortDir)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{
+		"users":     users,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, "Users retrieved successfully", 200)
+}
+
+func (h *AdminUserHandler) GetUser(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	user, err := h.adminUserService.GetUser(ctx.Request.Context(), uri.ID)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"user": user}, "User retrieved successfully", 200)
+}
+
+func (h *AdminUserHandler) ChangeRole(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	var request requests.ChangeRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	adminID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	user, err := h.adminUserService.ChangeRole(ctx.Request.Context(), uint(adminID), uri.ID, types.UserRole(request.Role))
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"user": user}, "User role updated successfully", 200)
+}
+
+func (h *AdminUserHandler) SuspendUser(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	adminID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	user, err := h.adminUserService.Suspend(ctx.Request.Context(), uint(adminID), uri.ID)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"user": user}, "User suspended successfully", 200)
+}
+
+func (h *AdminUserHandler) ReactivateUser(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	user, err := h.adminUserService.Reactivate(ctx.Request.Context(), uri.ID)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"user": user}, "User reactivated successfully", 200)
+}
+
+func (h *AdminUserHandler) DeleteUser(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	adminID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	if err := h.adminUserService.DeleteUser(ctx.Request.Context(), uint(adminID), uri.ID); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, nil, "User deleted successfully", 200)
+}
+
+func (h *AdminUserHandler) UnlockUser(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	if err := h.loginThrottleService.UnlockUser(ctx.Request.Context(), uri.ID); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, nil, "User unlocked successfully", 200)
+}
+
+func (h *AdminUserHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return 404
+	case errors.Is(err, services.ErrInvalidRole):
+		return 400
+	case errors.Is(err, services.ErrCannotModifySelf):
+		return 403
+	case errors.Is(err, services.ErrUserAlreadyInStatus):
+		return 409
+	default:
+		return 500
+	}
+}
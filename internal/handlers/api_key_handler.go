@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/requests"
+	"github.com/kareemhamed001/faq/internal/services"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+func NewAPIKeyHandler(service services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: &service,
+	}
+}
+
+func (h *APIKeyHandler) ListAPIKeys(ctx *gin.Context) {
+	userID, role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(ctx.Request.Context(), uint(userID), role)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"api_keys": keys}, "API keys retrieved successfully", 200)
+}
+
+func (h *APIKeyHandler) CreateAPIKey(ctx *gin.Context) {
+	var request requests.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	userID, role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	key, rawKey, err := h.apiKeyService.CreateAPIKey(ctx.Request.Context(), uint(userID), role, request.Name, types.APIKeyScope(request.Scope), request.ExpiresAt)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	// The raw key is only ever shown in this response.
+	helpers.WriteAPIResponse(ctx, gin.H{"api_key": key, "key": rawKey}, "API key created successfully", 201)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	userID, role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(ctx.Request.Context(), uint(userID), role, uri.ID); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, nil, "API key revoked successfully", 200)
+}
+
+func (h *APIKeyHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		return 404
+	case errors.Is(err, services.ErrInvalidAPIKeyScope), errors.Is(err, services.ErrInvalidExpiry), errors.Is(err, services.ErrStoreNotFound):
+		return 400
+	case errors.Is(err, services.ErrUnsupportedRole):
+		return 403
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/requests"
+	"github.com/kareemhamed001/faq/internal/services"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+type AskHandler struct {
+	askService       *services.AskService
+	analyticsService *services.SearchAnalyticsService
+}
+
+func NewAskHandler(service services.AskService, analyticsService services.SearchAnalyticsService) *AskHandler {
+	return &AskHandler{askService: &service, analyticsService: &analyticsService}
+}
+
+func (h *AskHandler) Ask(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	var request requests.AskRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	language := helpers.GetRequestLanguage(ctx, request.Language)
+
+	answers, err := h.askService.Ask(ctx.Request.Context(), uri.ID, request.Question, language, request.Limit)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	searchID := recordSearch(ctx, h.analyticsService, types.SearchSourceAsk, &uri.ID, request.Question, language, len(answers))
+
+	helpers.WriteAPIResponse(ctx, gin.H{
+		"answered":  len(answers) > 0,
+		"answers":   answers,
+		"search_id": searchID,
+	}, "Question answered successfully", 200)
+}
+
+func (h *AskHandler) ListUnansweredQuestions(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+
+	userID, role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	questions, total, err := h.askService.ListUnansweredQuestions(ctx.Request.Context(), role, uint(userID), page, pageSize)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{
+		"questions": questions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, "Unanswered questions retrieved successfully", 200)
+}
+
+func (h *AskHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrStoreNotFound):
+		return 404
+	case errors.Is(err, services.ErrSearchQueryRequired):
+		return 400
+	case errors.Is(err, services.ErrUnsupportedRole):
+		return 403
+	default:
+		return 500
+	}
+}
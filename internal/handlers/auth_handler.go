@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"regexp"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	appErrors "github.com/kareemhamed001/faq/internal/errors"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/models"
 	"github.com/kareemhamed001/faq/internal/requests"
 	"github.com/kareemhamed001/faq/internal/responses"
 	"github.com/kareemhamed001/faq/internal/services"
@@ -12,12 +17,14 @@ import (
 )
 
 type AuthHandler struct {
-	authService *services.AuthService
+	authService         *services.AuthService
+	verificationService *services.EmailVerificationService
 }
 
-func NewAuthHandler(service services.AuthService) *AuthHandler {
+func NewAuthHandler(service services.AuthService, verificationService services.EmailVerificationService) *AuthHandler {
 	return &AuthHandler{
-		authService: &service,
+		authService:         &service,
+		verificationService: &verificationService,
 	}
 }
 
@@ -37,7 +44,7 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 		return
 	}
 	if !validatePassword(request.Password) {
-		ctx.JSON(400, gin.H{"error": "Password must be at least 8 characters long and contain at least one number and one special character"})
+		ctx.JSON(400, gin.H{"error": passwordPolicyMessage})
 		return
 	}
 	if !validateRole(types.UserRole(request.Role)) {
@@ -50,7 +57,10 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 		responses.WriteError(ctx, 400, "REGISTER_FAILED", err.Error())
 		return
 	}
-	responses.WriteSuccess(ctx, 201, gin.H{"user": user}, nil)
+
+	// The account exists at this point; a mail failure is reported but can be retried via resend.
+	verificationErr := h.verificationService.SendVerification(ctx.Request.Context(), user)
+	responses.WriteSuccess(ctx, 201, gin.H{"user": user, "verification_sent": verificationErr == nil}, nil)
 }
 
 func (h *AuthHandler) Login(ctx *gin.Context) {
@@ -64,12 +74,133 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	user, token, err := h.authService.Login(request.Email, request.Password)
+	result, err := h.authService.Login(ctx.Request.Context(), request.Email, request.Password, ctx.ClientIP())
+	if err != nil {
+		writeLockoutHeader(ctx, err)
+		responses.WriteError(ctx, h.statusForError(err), "LOGIN_FAILED", err.Error())
+		return
+	}
+
+	if result.Tokens == nil {
+		responses.WriteSuccess(ctx, 200, gin.H{
+			"mfa_required":            result.MFARequired,
+			"mfa_enrollment_required": result.MFAEnrollmentRequired,
+			"mfa_token":               result.MFAToken,
+			"expires_in":              result.MFATokenExpiresIn,
+		}, nil)
+		return
+	}
+	responses.WriteSuccess(ctx, 200, tokenResponse(result.User, result.Tokens), nil)
+}
+
+func (h *AuthHandler) Refresh(ctx *gin.Context) {
+	var request requests.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	user, tokens, err := h.authService.Refresh(ctx.Request.Context(), request.RefreshToken)
+	if err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "REFRESH_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, tokenResponse(user, tokens), nil)
+}
+
+func (h *AuthHandler) Logout(ctx *gin.Context) {
+	var request requests.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	if err := h.authService.Logout(ctx.Request.Context(), request.RefreshToken); err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "LOGOUT_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"message": "Logged out successfully"}, nil)
+}
+
+func (h *AuthHandler) LogoutAll(ctx *gin.Context) {
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, err.Error())
+		return
+	}
+
+	if err := h.authService.LogoutAll(ctx.Request.Context(), uint(userID)); err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "LOGOUT_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"message": "Logged out from all sessions"}, nil)
+}
+
+func (h *AuthHandler) VerifyEmail(ctx *gin.Context) {
+	var request requests.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	user, err := h.verificationService.VerifyEmail(ctx.Request.Context(), request.Token)
 	if err != nil {
-		responses.WriteError(ctx, 401, "LOGIN_FAILED", err.Error())
+		responses.WriteError(ctx, h.statusForError(err), "VERIFY_EMAIL_FAILED", err.Error())
 		return
 	}
-	responses.WriteSuccess(ctx, 200, gin.H{"user": user, "token": token}, nil)
+	responses.WriteSuccess(ctx, 200, gin.H{"user": user}, nil)
+}
+
+func (h *AuthHandler) ResendVerification(ctx *gin.Context) {
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, err.Error())
+		return
+	}
+
+	if err := h.verificationService.ResendVerification(ctx.Request.Context(), uint(userID)); err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "VERIFY_EMAIL_RESEND_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"message": "Verification email sent"}, nil)
+}
+
+func (h *AuthHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials), errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+		return 401
+	case errors.Is(err, services.ErrTooManyLoginAttempts):
+		return 429
+	case errors.Is(err, services.ErrAccountSuspended):
+		return 403
+	case errors.Is(err, services.ErrInvalidVerificationToken):
+		return 400
+	case errors.Is(err, services.ErrEmailAlreadyVerified):
+		return 409
+	case errors.Is(err, services.ErrVerificationThrottled):
+		return 429
+	case errors.Is(err, services.ErrUserNotFound):
+		return 404
+	default:
+		return 500
+	}
+}
+
+// writeLockoutHeader sets Retry-After when err is a login lockout.
+func writeLockoutHeader(ctx *gin.Context, err error) {
+	var locked *services.LoginLockedError
+	if errors.As(err, &locked) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	}
+}
+
+func tokenResponse(user *models.User, tokens *services.TokenPair) gin.H {
+	return gin.H{
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	}
 }
 
 func validateName(name string) bool {
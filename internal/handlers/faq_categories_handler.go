@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
+	dtos "github.com/kareemhamed001/faq/internal/DTOs"
 	"github.com/kareemhamed001/faq/internal/helpers"
 	"github.com/kareemhamed001/faq/internal/services"
 )
@@ -17,10 +20,12 @@ func NewFAQCategoryHandler(service services.FAQCategoryService) *FAQCategoryHand
 }
 
 func (h *FAQCategoryHandler) GetAllCategories(ctx *gin.Context) {
+	language := helpers.GetRequestLanguage(ctx, "")
+
 	//check if search param exists
 	search := ctx.Query("search")
 	if search != "" {
-		categories, err := h.fAQCategoryService.SearchCategories(search)
+		categories, err := h.fAQCategoryService.SearchCategories(search, language)
 		if err != nil {
 			helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
 			return
@@ -30,7 +35,7 @@ func (h *FAQCategoryHandler) GetAllCategories(ctx *gin.Context) {
 		return
 	}
 
-	categories, err := h.fAQCategoryService.GetAllCategories()
+	categories, err := h.fAQCategoryService.GetAllCategories(language)
 	if err != nil {
 		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
 		return
@@ -39,6 +44,15 @@ func (h *FAQCategoryHandler) GetAllCategories(ctx *gin.Context) {
 
 }
 
+func (h *FAQCategoryHandler) GetCategoryTree(ctx *gin.Context) {
+	categories, err := h.fAQCategoryService.GetCategoryTree(helpers.GetRequestLanguage(ctx, ""))
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
+		return
+	}
+	helpers.WriteAPIResponse(ctx, gin.H{"categories": categories}, "Category tree retrieved successfully", 200)
+}
+
 func (h *FAQCategoryHandler) GetCategoryByID(ctx *gin.Context) {
 	var query struct {
 		ID uint `uri:"id" binding:"required"`
@@ -49,7 +63,7 @@ func (h *FAQCategoryHandler) GetCategoryByID(ctx *gin.Context) {
 		return
 	}
 
-	category, err := h.fAQCategoryService.GetCategoryByID(query.ID)
+	category, err := h.fAQCategoryService.GetCategoryByID(query.ID, helpers.GetRequestLanguage(ctx, ""))
 	if err != nil {
 		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
 		return
@@ -59,7 +73,9 @@ func (h *FAQCategoryHandler) GetCategoryByID(ctx *gin.Context) {
 
 func (h *FAQCategoryHandler) CreateCategory(ctx *gin.Context) {
 	var request struct {
-		Name string `json:"name" binding:"required"`
+		Name         string                        `json:"name" binding:"required"`
+		ParentID     *uint                         `json:"parent_id"`
+		Translations []dtos.CategoryTranslationDTO `json:"translations"`
 	}
 	err := ctx.ShouldBindJSON(&request)
 	if err != nil {
@@ -67,9 +83,9 @@ func (h *FAQCategoryHandler) CreateCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := h.fAQCategoryService.CreateCategory(request.Name)
+	category, err := h.fAQCategoryService.CreateCategory(request.Name, request.ParentID, request.Translations)
 	if err != nil {
-		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
 		return
 	}
 	helpers.WriteAPIResponse(ctx, gin.H{"category": category}, "Category created successfully", 201)
@@ -85,8 +101,10 @@ func (h *FAQCategoryHandler) UpdateCategory(ctx *gin.Context) {
 		return
 	}
 
+	// Translations are left as they are when omitted.
 	var request struct {
-		Name string `json:"name" binding:"required"`
+		Name         string                        `json:"name" binding:"required"`
+		Translations []dtos.CategoryTranslationDTO `json:"translations"`
 	}
 	err = ctx.ShouldBindJSON(&request)
 	if err != nil {
@@ -94,14 +112,43 @@ func (h *FAQCategoryHandler) UpdateCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := h.fAQCategoryService.UpdateCategory(uri.ID, request.Name)
+	category, err := h.fAQCategoryService.UpdateCategory(uri.ID, request.Name, request.Translations)
 	if err != nil {
-		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
 		return
 	}
 	helpers.WriteAPIResponse(ctx, gin.H{"category": category}, "Category updated successfully", 200)
 }
 
+// MoveCategory moves a category and its subcategories under parent_id, or to
+// the top level when parent_id is null.
+func (h *FAQCategoryHandler) MoveCategory(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	var request struct {
+		ParentID *uint `json:"parent_id"`
+	}
+	err = ctx.ShouldBindJSON(&request)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	category, err := h.fAQCategoryService.MoveCategory(uri.ID, request.ParentID)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+	helpers.WriteAPIResponse(ctx, gin.H{"category": category}, "Category moved successfully", 200)
+}
+
 func (h *FAQCategoryHandler) DeleteCategory(ctx *gin.Context) {
 	var uri struct {
 		ID uint `uri:"id" binding:"required"`
@@ -114,8 +161,22 @@ func (h *FAQCategoryHandler) DeleteCategory(ctx *gin.Context) {
 
 	err = h.fAQCategoryService.DeleteCategory(uri.ID)
 	if err != nil {
-		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
 		return
 	}
 	helpers.WriteAPIResponse(ctx, nil, "Category deleted successfully", 200)
 }
+
+func (h *FAQCategoryHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		return 404
+	case errors.Is(err, services.ErrCategoryInUse), errors.Is(err, services.ErrCategoryHasChildren):
+		return 409
+	case errors.Is(err, services.ErrParentCategoryNotFound), errors.Is(err, services.ErrCategoryCycle),
+		errors.Is(err, services.ErrInvalidCategoryTranslations):
+		return 400
+	default:
+		return 500
+	}
+}
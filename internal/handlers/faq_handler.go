@@ -2,23 +2,33 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	dtos "github.com/kareemhamed001/faq/internal/DTOs"
 	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/requests"
 	"github.com/kareemhamed001/faq/internal/services"
+	"github.com/kareemhamed001/faq/internal/types"
 )
 
 type FAQHandler struct {
-	fAQService   *services.FAQService
-	storeService *services.StoreService
+	fAQService       *services.FAQService
+	storeService     *services.StoreService
+	analyticsService *services.SearchAnalyticsService
 }
 
-func NewFAQHandler(faqService services.FAQService, storeService services.StoreService) *FAQHandler {
+func NewFAQHandler(faqService services.FAQService, storeService services.StoreService, analyticsService services.SearchAnalyticsService) *FAQHandler {
 	return &FAQHandler{
-		fAQService:   &faqService,
-		storeService: &storeService,
+		fAQService:       &faqService,
+		storeService:     &storeService,
+		analyticsService: &analyticsService,
 	}
 }
 
@@ -27,10 +37,7 @@ func (h *FAQHandler) GetAllFAQs(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
 	sortDir := ctx.DefaultQuery("sort", "desc")
-	language := ctx.GetHeader("Accept-Language")
-	if language == "" {
-		language = "en"
-	}
+	language := helpers.GetRequestLanguage(ctx, "")
 
 	userId, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
 	if err != nil {
@@ -38,18 +45,43 @@ func (h *FAQHandler) GetAllFAQs(ctx *gin.Context) {
 		return
 	}
 
-	faqs, total, err := h.fAQService.GetAllFAQs(ctx.Request.Context(), search, Role, uint(userId), page, pageSize, sortDir, language)
+	categoryID, err := helpers.GetOptionalIDQuery(ctx, "category_id")
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	filter := services.FAQFilter{
+		Search:     search,
+		Status:     types.FAQStatus(ctx.Query("status")),
+		Window:     ctx.Query("window"),
+		CategoryID: categoryID,
+	}
+
+	faqs, total, err := h.fAQService.GetAllFAQs(ctx.Request.Context(), filter, Role, uint(userId), page, pageSize, sortDir, language)
 	if err != nil {
 		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
 		return
 	}
 
-	helpers.WriteAPIResponse(ctx, gin.H{
+	response := gin.H{
 		"faqs":      faqs,
 		"total":     total,
 		"page":      page,
 		"page_size": pageSize,
-	}, "FAQs retrieved successfully", 200)
+	}
+	// Searches are recorded once, when the first page is requested.
+	if search != "" && page <= 1 {
+		var storeID *uint
+		if Role == types.RoleMerchant {
+			if store, err := h.storeService.GetStoreByMerchantID(uint(userId)); err == nil {
+				storeID = &store.ID
+			}
+		}
+		response["search_id"] = recordSearch(ctx, h.analyticsService, types.SearchSourceFAQs, storeID, search, language, int(total))
+	}
+
+	helpers.WriteAPIResponse(ctx, response, "FAQs retrieved successfully", 200)
 }
 
 func (h *FAQHandler) GetFAQByID(ctx *gin.Context) {
@@ -61,10 +93,7 @@ func (h *FAQHandler) GetFAQByID(ctx *gin.Context) {
 		return
 	}
 
-	language := ctx.GetHeader("Accept-Language")
-	if language == "" {
-		language = "en"
-	}
+	language := helpers.GetRequestLanguage(ctx, "")
 	includeAllTranslations := ctx.DefaultQuery("include_all_translations", "false") == "true"
 
 	userId, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
@@ -86,6 +115,8 @@ func (h *FAQHandler) CreateFAQ(ctx *gin.Context) {
 	var request struct {
 		CategoryID   uint                  `json:"category_id" binding:"required"`
 		Translations []dtos.TranslationDTO `json:"translations" binding:"required"`
+		PublishAt    *time.Time            `json:"publish_at"`
+		ExpireAt     *time.Time            `json:"expire_at"`
 	}
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -99,7 +130,8 @@ func (h *FAQHandler) CreateFAQ(ctx *gin.Context) {
 		return
 	}
 
-	faq, err := h.fAQService.CreateFAQ(ctx.Request.Context(), uint(userID), request.CategoryID, request.Translations, Role)
+	schedule := services.FAQSchedule{PublishAt: request.PublishAt, ExpireAt: request.ExpireAt}
+	faq, err := h.fAQService.CreateFAQ(ctx.Request.Context(), uint(userID), request.CategoryID, request.Translations, schedule, Role)
 	if err != nil {
 		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
 		return
@@ -164,16 +196,447 @@ func (h *FAQHandler) DeleteFAQ(ctx *gin.Context) {
 	helpers.WriteAPIResponse(ctx, nil, "FAQ deleted successfully", 200)
 }
 
+// maxImportSize caps the size of an uploaded import file.
+const maxImportSize = 10 << 20
+
+func (h *FAQHandler) ImportFAQs(ctx *gin.Context) {
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+
+	// Accept either a multipart upload in the "file" field or the raw file as the body.
+	var body io.Reader = ctx.Request.Body
+	filename := ""
+	if strings.HasPrefix(ctx.ContentType(), "multipart/form-data") {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+			return
+		}
+		defer file.Close()
+		body = file
+		filename = fileHeader.Filename
+	}
+
+	opts := services.ImportOptions{
+		Format: importFormat(ctx.Query("format"), filename, ctx.ContentType()),
+		DryRun: ctx.DefaultQuery("dry_run", "false") == "true",
+		Atomic: ctx.DefaultQuery("atomic", "false") == "true",
+	}
+
+	result, err := h.fAQService.ImportFAQs(ctx.Request.Context(), body, opts, Role, uint(userID))
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	switch {
+	case result.DryRun:
+		helpers.WriteAPIResponse(ctx, gin.H{"result": result}, "Import validated", 200)
+	case !result.Committed:
+		helpers.WriteAPIResponse(ctx, gin.H{"result": result}, "Import rejected, nothing was saved", 422)
+	default:
+		helpers.WriteAPIResponse(ctx, gin.H{"result": result}, "FAQs imported successfully", 200)
+	}
+}
+
+// importFormat picks the import format from the format query parameter, then
+// the uploaded file's extension, then the request content type.
+func importFormat(format, filename, contentType string) types.FAQFileFormat {
+	if format != "" {
+		return types.FAQFileFormat(strings.ToLower(format))
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return types.FAQFileCSV
+	case ".json":
+		return types.FAQFileJSON
+	case ".yaml", ".yml":
+		return types.FAQFileYAML
+	case ".xlsx":
+		return types.FAQFileXLSX
+	}
+
+	switch contentType {
+	case "text/csv":
+		return types.FAQFileCSV
+	case "application/json":
+		return types.FAQFileJSON
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		return types.FAQFileYAML
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return types.FAQFileXLSX
+	}
+	return ""
+}
+
+// ExportFAQs streams the FAQs visible to the caller as a file download. It
+// takes the same search, status, window and category filters as GetAllFAQs.
+func (h *FAQHandler) ExportFAQs(ctx *gin.Context) {
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	format := types.FAQFileFormat(strings.ToLower(ctx.DefaultQuery("format", "csv")))
+	contentType, err := services.ExportContentType(format)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	categoryID, err := helpers.GetOptionalIDQuery(ctx, "category_id")
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	filter := services.FAQFilter{
+		Search:     ctx.Query("search"),
+		Status:     types.FAQStatus(ctx.Query("status")),
+		Window:     ctx.Query("window"),
+		CategoryID: categoryID,
+	}
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=faqs-%s.%s", time.Now().Format("20060102"), format))
+	ctx.Status(200)
+
+	err = h.fAQService.ExportFAQs(ctx.Request.Context(), ctx.Writer, format, filter, Role, uint(userID))
+	if err != nil {
+		// Once the download has started the status can no longer change.
+		if ctx.Writer.Written() {
+			_ = ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+	}
+}
+
+func (h *FAQHandler) ScheduleFAQ(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	var request struct {
+		PublishAt *time.Time `json:"publish_at"`
+		ExpireAt  *time.Time `json:"expire_at"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	schedule := services.FAQSchedule{PublishAt: request.PublishAt, ExpireAt: request.ExpireAt}
+	faq, err := h.fAQService.ScheduleFAQ(ctx.Request.Context(), uri.ID, uint(userID), Role, schedule)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"faq": faq}, "FAQ schedule updated successfully", 200)
+}
+
+func (h *FAQHandler) SubmitFAQ(ctx *gin.Context) {
+	h.transitionFAQ(ctx, types.FAQTransitionSubmit, "FAQ submitted for review")
+}
+
+func (h *FAQHandler) ApproveFAQ(ctx *gin.Context) {
+	h.transitionFAQ(ctx, types.FAQTransitionApprove, "FAQ published successfully")
+}
+
+func (h *FAQHandler) RejectFAQ(ctx *gin.Context) {
+	h.transitionFAQ(ctx, types.FAQTransitionReject, "FAQ returned to draft")
+}
+
+func (h *FAQHandler) ArchiveFAQ(ctx *gin.Context) {
+	h.transitionFAQ(ctx, types.FAQTransitionArchive, "FAQ archived successfully")
+}
+
+func (h *FAQHandler) UnarchiveFAQ(ctx *gin.Context) {
+	h.transitionFAQ(ctx, types.FAQTransitionUnarchive, "FAQ moved back to draft")
+}
+
+func (h *FAQHandler) transitionFAQ(ctx *gin.Context, action types.FAQTransition, message string) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	faq, err := h.fAQService.TransitionFAQ(ctx.Request.Context(), uri.ID, uint(userID), Role, action)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"faq": faq}, message, 200)
+}
+
+func (h *FAQHandler) ListRevisions(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	revisions, total, err := h.fAQService.ListRevisions(ctx.Request.Context(), uri.ID, Role, uint(userID), page, pageSize)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{
+		"revisions": revisions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, "Revisions retrieved successfully", 200)
+}
+
+func (h *FAQHandler) DiffRevisions(ctx *gin.Context) {
+	var uri struct {
+		ID  uint `uri:"id" binding:"required"`
+		Rev int  `uri:"rev" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	against, err := strconv.Atoi(ctx.DefaultQuery("against", "0"))
+	if err != nil || against < 0 {
+		helpers.WriteAPIResponse(ctx, nil, "against must be a revision number", 400)
+		return
+	}
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	diff, err := h.fAQService.DiffRevisions(ctx.Request.Context(), uri.ID, uri.Rev, against, Role, uint(userID))
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"diff": diff}, "Revision diff retrieved successfully", 200)
+}
+
+func (h *FAQHandler) RestoreRevision(ctx *gin.Context) {
+	var uri struct {
+		ID  uint `uri:"id" binding:"required"`
+		Rev int  `uri:"rev" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	faq, err := h.fAQService.RestoreRevision(ctx.Request.Context(), uri.ID, uri.Rev, Role, uint(userID))
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"faq": faq}, "FAQ restored to revision successfully", 200)
+}
+
+// SubmitFeedback is public. Without a client_id, votes are deduplicated by the
+// caller's IP address and user agent.
+func (h *FAQHandler) SubmitFeedback(ctx *gin.Context) {
+	var uri struct {
+		StoreID uint `uri:"id" binding:"required"`
+		FAQID   uint `uri:"faqId" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	var request requests.FAQFeedbackRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	language := helpers.GetRequestLanguage(ctx, request.Language)
+
+	clientID := "client:" + request.ClientID
+	if request.ClientID == "" {
+		clientID = "ip:" + ctx.ClientIP() + "|" + ctx.Request.UserAgent()
+	}
+
+	feedback, err := h.fAQService.SubmitFeedback(ctx.Request.Context(), uri.StoreID, uri.FAQID, services.FAQFeedbackInput{
+		Helpful:  *request.Helpful,
+		Comment:  request.Comment,
+		Language: language,
+		ClientID: clientID,
+	})
+	if err != nil {
+		status := h.statusForError(err)
+		if errors.Is(err, services.ErrStoreNotFound) {
+			status = 404
+		}
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), status)
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"feedback": feedback}, "Feedback recorded successfully", 200)
+}
+
+func (h *FAQHandler) ListFeedback(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	feedback, total, err := h.fAQService.ListFeedback(ctx.Request.Context(), uri.ID, Role, uint(userID), page, pageSize)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{
+		"feedback":  feedback,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, "Feedback retrieved successfully", 200)
+}
+
+func (h *FAQHandler) LowestRatedFAQs(ctx *gin.Context) {
+	storeID, err := helpers.GetOptionalIDQuery(ctx, "store_id")
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+	minVotes, _ := strconv.Atoi(ctx.DefaultQuery("min_votes", "5"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	ratings, err := h.fAQService.LowestRatedFAQs(ctx.Request.Context(), Role, uint(userID), storeID, minVotes, limit)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"faqs": ratings}, "Lowest rated FAQs retrieved successfully", 200)
+}
+
+// ReorderFAQs sets a store's order of the FAQs in one category. faq_ids may be
+// empty to go back to newest first.
+func (h *FAQHandler) ReorderFAQs(ctx *gin.Context) {
+	var request struct {
+		StoreID    *uint  `json:"store_id"`
+		CategoryID uint   `json:"category_id" binding:"required"`
+		FAQIDs     []uint `json:"faq_ids" binding:"max=500"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	userID, Role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	if err := h.fAQService.ReorderFAQs(ctx.Request.Context(), Role, uint(userID), request.StoreID, request.CategoryID, request.FAQIDs); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, nil, "FAQs reordered successfully", 200)
+}
+
 func (h *FAQHandler) statusForError(err error) int {
 	switch {
-	case errors.Is(err, services.ErrFAQNotFound):
+	case errors.Is(err, services.ErrFAQNotFound), errors.Is(err, services.ErrRevisionNotFound):
 		return 404
 	case errors.Is(err, services.ErrUnauthorizedFAQ):
 		return 403
 	case errors.Is(err, services.ErrCategoryNotFound), errors.Is(err, services.ErrStoreNotFound):
 		return 400
-	case errors.Is(err, services.ErrUnsupportedRole):
+	case errors.Is(err, services.ErrUnsupportedRole), errors.Is(err, services.ErrEmailNotVerified):
 		return 403
+	case errors.Is(err, services.ErrInvalidFAQStatus), errors.Is(err, services.ErrInvalidFAQWindow), errors.Is(err, services.ErrInvalidSchedule):
+		return 400
+	case errors.Is(err, services.ErrUnsupportedFileFormat), errors.Is(err, services.ErrInvalidImportFile):
+		return 400
+	case errors.Is(err, services.ErrStoreIDRequired), errors.Is(err, services.ErrInvalidFAQOrder):
+		return 400
+	case errors.Is(err, services.ErrInvalidFAQAction):
+		return 409
 	default:
 		return 500
 	}
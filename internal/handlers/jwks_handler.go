@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+)
+
+type JWKSHandler struct {
+	keys *helpers.KeySet
+}
+
+func NewJWKSHandler(keys *helpers.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS serves the public signing keys. The body follows RFC 7517 rather than
+// the API envelope so standard JWT libraries can consume it directly.
+func (h *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(200, h.keys.PublicJWKS())
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/kareemhamed001/faq/internal/errors"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/requests"
+	"github.com/kareemhamed001/faq/internal/responses"
+	"github.com/kareemhamed001/faq/internal/services"
+)
+
+type MFAHandler struct {
+	mfaService *services.MFAService
+}
+
+func NewMFAHandler(service services.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: &service,
+	}
+}
+
+func (h *MFAHandler) CompleteLogin(ctx *gin.Context) {
+	var request requests.MFALoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	user, tokens, err := h.mfaService.CompleteLogin(ctx.Request.Context(), request.MFAToken, request.Code, ctx.ClientIP())
+	if err != nil {
+		writeLockoutHeader(ctx, err)
+		responses.WriteError(ctx, h.statusForError(err), "MFA_LOGIN_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, tokenResponse(user, tokens), nil)
+}
+
+func (h *MFAHandler) BeginLoginEnrollment(ctx *gin.Context) {
+	var request requests.MFATokenRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	enrollment, err := h.mfaService.BeginLoginEnrollment(ctx.Request.Context(), request.MFAToken)
+	if err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "MFA_SETUP_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"totp": enrollment}, nil)
+}
+
+func (h *MFAHandler) CompleteLoginEnrollment(ctx *gin.Context) {
+	var request requests.MFALoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	user, tokens, recoveryCodes, err := h.mfaService.CompleteLoginEnrollment(ctx.Request.Context(), request.MFAToken, request.Code)
+	if err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "MFA_CONFIRM_FAILED", err.Error())
+		return
+	}
+
+	data := tokenResponse(user, tokens)
+	data["recovery_codes"] = recoveryCodes
+	responses.WriteSuccess(ctx, 200, data, nil)
+}
+
+func (h *MFAHandler) BeginEnrollment(ctx *gin.Context) {
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, err.Error())
+		return
+	}
+
+	enrollment, err := h.mfaService.BeginEnrollment(ctx.Request.Context(), uint(userID))
+	if err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "MFA_SETUP_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"totp": enrollment}, nil)
+}
+
+func (h *MFAHandler) ConfirmEnrollment(ctx *gin.Context) {
+	var request requests.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, err.Error())
+		return
+	}
+
+	recoveryCodes, err := h.mfaService.ConfirmEnrollment(ctx.Request.Context(), uint(userID), request.Code)
+	if err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "MFA_CONFIRM_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"recovery_codes": recoveryCodes}, nil)
+}
+
+func (h *MFAHandler) Disable(ctx *gin.Context) {
+	var request requests.DisableMFARequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, err.Error())
+		return
+	}
+
+	if err := h.mfaService.Disable(ctx.Request.Context(), uint(userID), request.Password, request.Code); err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "MFA_DISABLE_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"message": "Two-factor authentication disabled"}, nil)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var request requests.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, err.Error())
+		return
+	}
+
+	recoveryCodes, err := h.mfaService.RegenerateRecoveryCodes(ctx.Request.Context(), uint(userID), request.Code)
+	if err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "MFA_RECOVERY_CODES_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"recovery_codes": recoveryCodes}, nil)
+}
+
+func (h *MFAHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidMFAToken), errors.Is(err, services.ErrInvalidMFACode), errors.Is(err, services.ErrIncorrectPassword):
+		return 401
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		return 409
+	case errors.Is(err, services.ErrMFANotEnabled), errors.Is(err, services.ErrMFANotStarted):
+		return 400
+	case errors.Is(err, services.ErrMFARequiredByPolicy), errors.Is(err, services.ErrAccountSuspended):
+		return 403
+	case errors.Is(err, services.ErrTooManyLoginAttempts):
+		return 429
+	case errors.Is(err, services.ErrUserNotFound):
+		return 404
+	default:
+		return 500
+	}
+}
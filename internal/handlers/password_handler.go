@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/kareemhamed001/faq/internal/errors"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/requests"
+	"github.com/kareemhamed001/faq/internal/responses"
+	"github.com/kareemhamed001/faq/internal/services"
+)
+
+const passwordPolicyMessage = "Password must be at least 8 characters long and contain at least one number and one special character"
+
+type PasswordHandler struct {
+	passwordService *services.PasswordService
+}
+
+func NewPasswordHandler(service services.PasswordService) *PasswordHandler {
+	return &PasswordHandler{
+		passwordService: &service,
+	}
+}
+
+func (h *PasswordHandler) ForgotPassword(ctx *gin.Context) {
+	var request requests.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+
+	if err := h.passwordService.ForgotPassword(ctx.Request.Context(), request.Email); err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "PASSWORD_FORGOT_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"message": "If the email is registered, a reset link has been sent"}, nil)
+}
+
+func (h *PasswordHandler) ResetPassword(ctx *gin.Context) {
+	var request requests.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+	if !validatePassword(request.Password) {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, passwordPolicyMessage)
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(ctx.Request.Context(), request.Token, request.Password); err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "PASSWORD_RESET_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"message": "Password reset successfully"}, nil)
+}
+
+func (h *PasswordHandler) ChangePassword(ctx *gin.Context) {
+	var request requests.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, err.Error())
+		return
+	}
+	if !validatePassword(request.NewPassword) {
+		responses.WriteError(ctx, appErrors.ErrInvalidInput.Status, appErrors.ErrInvalidInput.Code, passwordPolicyMessage)
+		return
+	}
+
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, err.Error())
+		return
+	}
+
+	err = h.passwordService.ChangePassword(ctx.Request.Context(), uint(userID), ctx.GetString("session_id"), request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		responses.WriteError(ctx, h.statusForError(err), "PASSWORD_CHANGE_FAILED", err.Error())
+		return
+	}
+	responses.WriteSuccess(ctx, 200, gin.H{"message": "Password changed successfully"}, nil)
+}
+
+func (h *PasswordHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidResetToken), errors.Is(err, services.ErrPasswordUnchanged):
+		return 400
+	case errors.Is(err, services.ErrIncorrectPassword):
+		return 401
+	case errors.Is(err, services.ErrUserNotFound):
+		return 404
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/requests"
+	"github.com/kareemhamed001/faq/internal/services"
+)
+
+type ProfileHandler struct {
+	profileService      *services.ProfileService
+	verificationService *services.EmailVerificationService
+}
+
+func NewProfileHandler(service services.ProfileService, verificationService services.EmailVerificationService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService:      &service,
+		verificationService: &verificationService,
+	}
+}
+
+func (h *ProfileHandler) GetMe(ctx *gin.Context) {
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	user, err := h.profileService.GetProfile(ctx.Request.Context(), uint(userID))
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"user": user}, "Profile retrieved successfully", 200)
+}
+
+func (h *ProfileHandler) UpdateMe(ctx *gin.Context) {
+	var request requests.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+	if request.Name != nil && !validateName(*request.Name) {
+		helpers.WriteAPIResponse(ctx, nil, "Name is required", 400)
+		return
+	}
+	if request.Email != nil && !validateEmail(*request.Email) {
+		helpers.WriteAPIResponse(ctx, nil, "Invalid email format", 400)
+		return
+	}
+
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	user, emailChanged, err := h.profileService.UpdateProfile(ctx.Request.Context(), uint(userID), services.ProfileUpdate{
+		Name:  request.Name,
+		Email: request.Email,
+	})
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	response := gin.H{"user": user}
+	if emailChanged {
+		// The change is saved at this point; a mail failure is reported but can be retried via resend.
+		verificationErr := h.verificationService.SendVerification(ctx.Request.Context(), user)
+		response["verification_sent"] = verificationErr == nil
+	}
+
+	helpers.WriteAPIResponse(ctx, response, "Profile updated successfully", 200)
+}
+
+func (h *ProfileHandler) DeleteMe(ctx *gin.Context) {
+	var request requests.CloseAccountRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	userID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	if err := h.profileService.CloseAccount(ctx.Request.Context(), uint(userID), request.Password); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, nil, "Account closed successfully", 200)
+}
+
+func (h *ProfileHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return 404
+	case errors.Is(err, services.ErrNothingToSave):
+		return 400
+	case errors.Is(err, services.ErrIncorrectPassword):
+		return 401
+	case errors.Is(err, services.ErrLastAdmin):
+		return 403
+	case errors.Is(err, services.ErrEmailTaken):
+		return 409
+	default:
+		return 500
+	}
+}
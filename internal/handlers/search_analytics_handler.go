@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/services"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+const reportDateLayout = "2006-01-02"
+
+type SearchAnalyticsHandler struct {
+	analyticsService *services.SearchAnalyticsService
+}
+
+func NewSearchAnalyticsHandler(service services.SearchAnalyticsService) *SearchAnalyticsHandler {
+	return &SearchAnalyticsHandler{analyticsService: &service}
+}
+
+// GetSearchReport accepts from and to as YYYY-MM-DD; to is inclusive. source
+// takes a comma-separated list of search sources.
+func (h *SearchAnalyticsHandler) GetSearchReport(ctx *gin.Context) {
+	var filter services.SearchReportFilter
+	var err error
+
+	if from := ctx.Query("from"); from != "" {
+		if filter.From, err = time.Parse(reportDateLayout, from); err != nil {
+			helpers.WriteAPIResponse(ctx, nil, "from must be a date (YYYY-MM-DD)", 400)
+			return
+		}
+	}
+	if to := ctx.Query("to"); to != "" {
+		if filter.To, err = time.Parse(reportDateLayout, to); err != nil {
+			helpers.WriteAPIResponse(ctx, nil, "to must be a date (YYYY-MM-DD)", 400)
+			return
+		}
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	if filter.StoreID, err = helpers.GetOptionalIDQuery(ctx, "store_id"); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+	if sources := ctx.Query("source"); sources != "" {
+		for _, source := range strings.Split(sources, ",") {
+			filter.Sources = append(filter.Sources, types.SearchSource(strings.TrimSpace(source)))
+		}
+	}
+	filter.Limit, _ = strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	userID, role, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	report, err := h.analyticsService.GetSearchReport(ctx.Request.Context(), role, uint(userID), filter)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{"report": report}, "Search report retrieved successfully", 200)
+}
+
+// RecordClick is called by clients when a customer opens an FAQ from the
+// results of a search, identified by the search_id returned with them.
+func (h *SearchAnalyticsHandler) RecordClick(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	var request struct {
+		FAQID uint `json:"faq_id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	if err := h.analyticsService.RecordClick(ctx.Request.Context(), uri.ID, request.FAQID); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, nil, "Click recorded successfully", 200)
+}
+
+func (h *SearchAnalyticsHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrSearchEventNotFound), errors.Is(err, services.ErrFAQNotFound):
+		return 404
+	case errors.Is(err, services.ErrInvalidDateRange), errors.Is(err, services.ErrInvalidSearchSource):
+		return 400
+	case errors.Is(err, services.ErrUnsupportedRole):
+		return 403
+	default:
+		return 500
+	}
+}
+
+// recordSearch logs a search for analytics and returns its id for click
+// tracking. language may be a raw Accept-Language header; only its first tag
+// is recorded. Failing to record never fails the search itself.
+func recordSearch(ctx *gin.Context, analytics *services.SearchAnalyticsService, source types.SearchSource, storeID *uint, query, language string, resultCount int) *uint {
+	language = helpers.NormalizeLanguage(language)
+	if language == "" {
+		language = "en"
+	}
+	event, err := analytics.RecordSearch(ctx.Request.Context(), source, storeID, query, language, resultCount)
+	if err != nil {
+		_ = ctx.Error(err)
+		return nil
+	}
+	return &event.ID
+}
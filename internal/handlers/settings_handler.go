@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/services"
+)
+
+type SettingsHandler struct {
+	settingsService *services.SettingsService
+}
+
+func NewSettingsHandler(service services.SettingsService) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: &service,
+	}
+}
+
+func (h *SettingsHandler) GetSecuritySettings(ctx *gin.Context) {
+	settings, err := h.settingsService.GetSecuritySettings(ctx.Request.Context())
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
+		return
+	}
+	helpers.WriteAPIResponse(ctx, gin.H{"settings": settings}, "Settings retrieved successfully", 200)
+}
+
+func (h *SettingsHandler) UpdateSecuritySettings(ctx *gin.Context) {
+	var request services.SecuritySettings
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSecuritySettings(ctx.Request.Context(), request)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 500)
+		return
+	}
+	helpers.WriteAPIResponse(ctx, gin.H{"settings": settings}, "Settings updated successfully", 200)
+}
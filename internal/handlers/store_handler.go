@@ -1,19 +1,23 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kareemhamed001/faq/internal/helpers"
 	"github.com/kareemhamed001/faq/internal/services"
+	"github.com/kareemhamed001/faq/internal/types"
 )
 
 type StoreHandler struct {
-	storeService *services.StoreService
+	storeService     *services.StoreService
+	analyticsService *services.SearchAnalyticsService
 }
 
-func NewStoreHandler(storeService services.StoreService) *StoreHandler {
-	return &StoreHandler{storeService: &storeService}
+func NewStoreHandler(storeService services.StoreService, analyticsService services.SearchAnalyticsService) *StoreHandler {
+	return &StoreHandler{storeService: &storeService, analyticsService: &analyticsService}
 }
 
 func (h *StoreHandler) ListStores(ctx *gin.Context) {
@@ -39,20 +43,114 @@ func (h *StoreHandler) GetStore(ctx *gin.Context) {
 		return
 	}
 
-	language := ctx.GetHeader("Accept-Language")
-	if language == "" {
-		language = "en"
+	language := helpers.GetRequestLanguage(ctx, "")
+
+	search := strings.TrimSpace(ctx.Query("search"))
+
+	categoryID, err := helpers.GetOptionalIDQuery(ctx, "category_id")
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	storeWithFAQs, err := h.storeService.GetStoreWithFAQs(ctx.Request.Context(), uri.ID, language, services.StoreFAQFilter{
+		Search:     search,
+		Sort:       ctx.Query("sort"),
+		CategoryID: categoryID,
+	})
+	if err != nil {
+		status := 500
+		if errors.Is(err, services.ErrStoreNotFound) || errors.Is(err, services.ErrCategoryNotFound) {
+			status = 404
+		}
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), status)
+		return
+	}
+
+	response := gin.H{"store": storeWithFAQs}
+	if search != "" {
+		response["search_id"] = recordSearch(ctx, h.analyticsService, types.SearchSourceStore, &uri.ID, search, language, len(storeWithFAQs.FAQs))
+	}
+
+	helpers.WriteAPIResponse(ctx, response, "Store retrieved successfully", 200)
+}
+
+func (h *StoreHandler) SuggestFAQs(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	language := helpers.GetRequestLanguage(ctx, "")
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	q := ctx.Query("q")
+
+	suggestions, err := h.storeService.SuggestFAQs(ctx.Request.Context(), uri.ID, q, language, limit)
+	if err != nil {
+		status := 500
+		switch {
+		case errors.Is(err, services.ErrStoreNotFound):
+			status = 404
+		case errors.Is(err, services.ErrSearchQueryRequired):
+			status = 400
+		}
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), status)
+		return
+	}
+
+	searchID := recordSearch(ctx, h.analyticsService, types.SearchSourceSuggest, &uri.ID, q, language, len(suggestions))
+
+	helpers.WriteAPIResponse(ctx, gin.H{"suggestions": suggestions, "search_id": searchID}, "Suggestions retrieved successfully", 200)
+}
+
+// RecordView counts a customer opening an FAQ. It does not touch the
+// database, so unknown stores and FAQs are only dropped when views are flushed.
+func (h *StoreHandler) RecordView(ctx *gin.Context) {
+	var uri struct {
+		ID    uint `uri:"id" binding:"required"`
+		FAQID uint `uri:"faqId" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
 	}
 
-	storeWithFAQs, err := h.storeService.GetStoreWithFAQs(ctx.Request.Context(), uri.ID, language)
+	h.storeService.RecordView(uri.ID, uri.FAQID)
+
+	helpers.WriteAPIResponse(ctx, nil, "View recorded", 202)
+}
+
+func (h *StoreHandler) TopFAQs(ctx *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	categoryID, err := helpers.GetOptionalIDQuery(ctx, "category_id")
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	language := helpers.GetRequestLanguage(ctx, "")
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	faqs, err := h.storeService.TopFAQs(ctx.Request.Context(), uri.ID, categoryID, language, limit)
 	if err != nil {
 		status := 500
-		if err == services.ErrStoreNotFound {
+		if errors.Is(err, services.ErrStoreNotFound) || errors.Is(err, services.ErrCategoryNotFound) {
 			status = 404
 		}
 		helpers.WriteAPIResponse(ctx, nil, err.Error(), status)
 		return
 	}
 
-	helpers.WriteAPIResponse(ctx, gin.H{"store": storeWithFAQs}, "Store retrieved successfully", 200)
+	helpers.WriteAPIResponse(ctx, gin.H{"faqs": faqs}, "Top FAQs retrieved successfully", 200)
 }
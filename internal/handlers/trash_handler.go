@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/services"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler(service services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: &service,
+	}
+}
+
+func (h *TrashHandler) ListTrash(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	sortDir := ctx.DefaultQuery("sort", "desc")
+	trashType := types.TrashType(ctx.DefaultQuery("type", string(types.TrashFAQs)))
+
+	items, total, err := h.trashService.ListTrash(ctx.Request.Context(), trashType, page, pageSize, sortDir)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, gin.H{
+		"items":     items,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, "Trash retrieved successfully", 200)
+}
+
+func (h *TrashHandler) RestoreItem(ctx *gin.Context) {
+	var uri struct {
+		Type string `uri:"type" binding:"required"`
+		ID   uint   `uri:"id" binding:"required"`
+	}
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 400)
+		return
+	}
+
+	adminID, _, err := helpers.GetUserIDAndRoleFromContext(ctx)
+	if err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), 401)
+		return
+	}
+
+	if err := h.trashService.Restore(ctx.Request.Context(), uint(adminID), types.TrashType(uri.Type), uri.ID); err != nil {
+		helpers.WriteAPIResponse(ctx, nil, err.Error(), h.statusForError(err))
+		return
+	}
+
+	helpers.WriteAPIResponse(ctx, nil, "Item restored successfully", 200)
+}
+
+func (h *TrashHandler) statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidTrashType):
+		return 400
+	case errors.Is(err, services.ErrTrashItemNotFound):
+		return 404
+	case errors.Is(err, services.ErrParentDeleted):
+		return 409
+	default:
+		return 500
+	}
+}
@@ -8,7 +8,8 @@ import (
 
 // GenerateToken signs a JWT with provided claims plus sensible defaults.
 // Adds iat/exp if they are missing; default TTL is 24h.
-func GenerateToken(data map[string]interface{}, secret string) (string, error) {
+// The token is signed with the currently active key and carries its kid.
+func GenerateToken(data map[string]interface{}, keys *KeySet) (string, error) {
 	now := time.Now()
 	claims := jwt.MapClaims{}
 
@@ -24,9 +25,15 @@ func GenerateToken(data map[string]interface{}, secret string) (string, error) {
 		claims["exp"] = now.Add(24 * time.Hour).Unix()
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signingKey, err := keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(signingKey.Algorithm), claims)
+	token.Header["kid"] = signingKey.ID
 
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString(signingKey.signKey)
 	if err != nil {
 		return "", err
 	}
@@ -35,12 +42,21 @@ func GenerateToken(data map[string]interface{}, secret string) (string, error) {
 }
 
 // ValidateToken parses and validates a JWT (signature + registered claims).
-func ValidateToken(tokenString string, secret string) (map[string]interface{}, error) {
+// The verification key is selected by the kid header and must match the token algorithm.
+func ValidateToken(tokenString string, keys *KeySet) (map[string]interface{}, error) {
 	claims := jwt.MapClaims{}
-	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	parser := jwt.NewParser(jwt.WithValidMethods(keys.Algorithms()))
 
 	token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
+		kid, _ := token.Header["kid"].(string)
+		key, err := keys.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return key.verifyKey, nil
 	})
 	if err != nil {
 		return nil, err
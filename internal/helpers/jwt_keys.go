@@ -0,0 +1,352 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no active jwt signing key")
+	ErrUnknownKeyID = errors.New("unknown jwt key id")
+)
+
+// SigningKey is one JWT key. A key signs new tokens from ActivateAt on (until a
+// newer key activates) and is accepted for verification until RetireAt.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	ActivateAt time.Time
+	RetireAt   time.Time
+
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func (k *SigningKey) isRetired(now time.Time) bool {
+	return !k.RetireAt.IsZero() && !now.Before(k.RetireAt)
+}
+
+// KeySet holds every configured JWT key and picks the signing key by schedule.
+// It is safe for concurrent use and can be reloaded from its manifest at runtime.
+type KeySet struct {
+	mu           sync.RWMutex
+	keys         []*SigningKey
+	manifestPath string
+}
+
+// keyManifestEntry is one entry of the JSON key manifest (JWT_KEYS_FILE).
+// Relative private_key paths are resolved against the manifest directory.
+type keyManifestEntry struct {
+	KeyID      string     `json:"kid"`
+	Algorithm  string     `json:"alg"`
+	PrivateKey string     `json:"private_key"`
+	Secret     string     `json:"secret"`
+	ActivateAt *time.Time `json:"activate_at"`
+	RetireAt   *time.Time `json:"retire_at"`
+}
+
+// NewKeySet builds the key set from configuration. When keysFile is empty a
+// single HS256 key is derived from secret, matching the historical behaviour.
+func NewKeySet(secret, keysFile string) (*KeySet, error) {
+	if keysFile == "" {
+		return &KeySet{keys: []*SigningKey{{
+			ID:        "default",
+			Algorithm: jwt.SigningMethodHS256.Alg(),
+			signKey:   []byte(secret),
+			verifyKey: []byte(secret),
+		}}}, nil
+	}
+
+	ks := &KeySet{manifestPath: keysFile}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Reload re-reads the key manifest, allowing keys to be added or retired without a restart.
+func (ks *KeySet) Reload() error {
+	if ks.manifestPath == "" {
+		return nil
+	}
+
+	raw, err := os.ReadFile(ks.manifestPath)
+	if err != nil {
+		return fmt.Errorf("reading jwt key manifest: %w", err)
+	}
+
+	var entries []keyManifestEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return fmt.Errorf("parsing jwt key manifest: %w", err)
+	}
+
+	baseDir := filepath.Dir(ks.manifestPath)
+	seen := make(map[string]bool)
+	keys := make([]*SigningKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.KeyID == "" {
+			return errors.New("jwt key manifest entry without kid")
+		}
+		if seen[entry.KeyID] {
+			return fmt.Errorf("duplicate jwt kid %q", entry.KeyID)
+		}
+		seen[entry.KeyID] = true
+
+		key, err := loadSigningKey(entry, baseDir)
+		if err != nil {
+			return fmt.Errorf("loading jwt key %q: %w", entry.KeyID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return errors.New("jwt key manifest contains no keys")
+	}
+
+	// Keys activated at the same time keep their manifest order, so the last
+	// of them listed is the one SigningKey picks.
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].ActivateAt.Before(keys[j].ActivateAt)
+	})
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+	return nil
+}
+
+// StartReloading reloads the manifest every interval until stop is closed.
+// Reload failures keep the previous keys and are reported through onError.
+func (ks *KeySet) StartReloading(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	if ks.manifestPath == "" || interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := ks.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// SigningKey returns the key new tokens are signed with: the most recently
+// activated key that is not retired.
+func (ks *KeySet) SigningKey() (*SigningKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+	var current *SigningKey
+	for _, key := range ks.keys {
+		if key.ActivateAt.After(now) || key.isRetired(now) {
+			continue
+		}
+		current = key
+	}
+	if current == nil {
+		return nil, ErrNoSigningKey
+	}
+	return current, nil
+}
+
+// VerificationKey returns the key identified by kid if it may still verify tokens.
+func (ks *KeySet) VerificationKey(kid string) (*SigningKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+	for _, key := range ks.keys {
+		if key.ID == kid && !key.isRetired(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrUnknownKeyID
+}
+
+// Algorithms lists the signing algorithms of all configured keys.
+func (ks *KeySet) Algorithms() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var algs []string
+	for _, key := range ks.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algs = append(algs, key.Algorithm)
+		}
+	}
+	return algs
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWKS returns the public part of every asymmetric key that is not retired,
+// including keys scheduled for future activation so verifiers can cache them early.
+// HMAC keys are never published.
+func (ks *KeySet) PublicJWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		if key.isRetired(now) {
+			continue
+		}
+		if jwk, ok := toJWK(key); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+func toJWK(key *SigningKey) (JWK, bool) {
+	jwk := JWK{Use: "sig", Algorithm: key.Algorithm, KeyID: key.ID}
+
+	switch pub := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return JWK{}, false
+	}
+	return jwk, true
+}
+
+func loadSigningKey(entry keyManifestEntry, baseDir string) (*SigningKey, error) {
+	key := &SigningKey{ID: entry.KeyID, Algorithm: entry.Algorithm}
+	if entry.ActivateAt != nil {
+		key.ActivateAt = *entry.ActivateAt
+	}
+	if entry.RetireAt != nil {
+		key.RetireAt = *entry.RetireAt
+	}
+
+	if entry.Algorithm == jwt.SigningMethodHS256.Alg() {
+		if entry.Secret == "" {
+			return nil, errors.New("HS256 key requires a secret")
+		}
+		key.signKey = []byte(entry.Secret)
+		key.verifyKey = []byte(entry.Secret)
+		return key, nil
+	}
+
+	path := entry.PrivateKey
+	if path == "" {
+		return nil, errors.New("private_key path is required")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+
+	private, err := parsePrivateKeyPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch entry.Algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		rsaKey, ok := private.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("RS256 requires an RSA private key")
+		}
+		if rsaKey.N.BitLen() < 2048 {
+			return nil, errors.New("RS256 keys must be at least 2048 bits")
+		}
+		key.signKey, key.verifyKey = rsaKey, &rsaKey.PublicKey
+	case jwt.SigningMethodES256.Alg():
+		ecKey, ok := private.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 requires a P-256 EC private key")
+		}
+		key.signKey, key.verifyKey = ecKey, &ecKey.PublicKey
+	case jwt.SigningMethodEdDSA.Alg():
+		edKey, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("EdDSA requires an Ed25519 private key")
+		}
+		key.signKey, key.verifyKey = edKey, edKey.Public().(ed25519.PublicKey)
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", entry.Algorithm)
+	}
+
+	return key, nil
+}
+
+// parsePrivateKeyPEM reads PKCS#8, PKCS#1 (RSA) or SEC 1 (EC) private keys.
+func parsePrivateKeyPEM(path string) (crypto.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key format")
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kareemhamed001/faq/internal/types"
@@ -45,3 +46,49 @@ func GetUserIDAndRoleFromContext(ctx *gin.Context) (uint64, types.UserRole, erro
 
 	return userIDUint, types.UserRole(roleStr), nil
 }
+
+// GetOptionalIDQuery reads an optional numeric id from the query string. It
+// returns nil when the parameter is absent.
+func GetOptionalIDQuery(ctx *gin.Context, key string) (*uint, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, errors.New(key + " must be a number")
+	}
+	value := uint(id)
+	return &value, nil
+}
+
+// maxLanguageLength is the width of the language columns.
+const maxLanguageLength = 10
+
+// NormalizeLanguage reduces a language, such as an Accept-Language header like
+// "en-US,en;q=0.9", to its first language tag and cuts it to the width of the
+// language columns. It returns "" when no tag is given.
+func NormalizeLanguage(value string) string {
+	tag, _, _ := strings.Cut(value, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag = strings.TrimSpace(tag)
+	if tag == "*" {
+		return ""
+	}
+	if len(tag) > maxLanguageLength {
+		tag = tag[:maxLanguageLength]
+	}
+	return tag
+}
+
+// GetRequestLanguage returns the language a request asks for: explicit when
+// given, otherwise the first tag of the Accept-Language header, otherwise "en".
+func GetRequestLanguage(ctx *gin.Context, explicit string) string {
+	if language := NormalizeLanguage(explicit); language != "" {
+		return language
+	}
+	if language := NormalizeLanguage(ctx.GetHeader("Accept-Language")); language != "" {
+		return language
+	}
+	return "en"
+}
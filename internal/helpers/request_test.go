@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"en", "en"},
+		{"ar-EG", "ar-EG"},
+		{"en-US,en;q=0.9", "en-US"},
+		{" fr-CA ; q=0.8 , fr", "fr-CA"},
+		{"*", ""},
+		{"de;q=0.7", "de"},
+		{"x-very-long-private-tag", "x-very-lon"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeLanguage(tt.value); got != tt.want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGetRequestLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		explicit string
+		want     string
+	}{
+		{"", "", "en"},
+		{"ar-EG,ar;q=0.9,en;q=0.8", "", "ar-EG"},
+		{"ar-EG,ar;q=0.9", "fr", "fr"},
+		{"*", "", "en"},
+	}
+	for _, tt := range tests {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest("GET", "/", nil)
+		if tt.header != "" {
+			ctx.Request.Header.Set("Accept-Language", tt.header)
+		}
+		if got := GetRequestLanguage(ctx, tt.explicit); got != tt.want {
+			t.Errorf("GetRequestLanguage(%q, %q) = %q, want %q", tt.header, tt.explicit, got, tt.want)
+		}
+	}
+}
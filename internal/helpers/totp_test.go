@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed used by the RFC 4226 and RFC 6238 test vectors.
+const rfcSecret = "12345678901234567890"
+
+func TestHOTPRFC4226Vectors(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp([]byte(rfcSecret), int64(counter)); got != code {
+			t.Errorf("hotp(counter %d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestValidateTOTPRFC6238Vectors(t *testing.T) {
+	// RFC 6238 lists 8-digit codes; 6-digit codes are their last six digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	secret := totpEncoding.EncodeToString([]byte(rfcSecret))
+	for _, tt := range tests {
+		now := time.Unix(tt.unix, 0)
+		step, ok := ValidateTOTP(secret, tt.code, now, 0)
+		if !ok {
+			t.Errorf("ValidateTOTP at %d rejected %s", tt.unix, tt.code)
+			continue
+		}
+		if step != TOTPStep(now) {
+			t.Errorf("ValidateTOTP at %d returned step %d, want %d", tt.unix, step, TOTPStep(now))
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte(rfcSecret))
+	now := time.Unix(1111111111, 0)
+	previous := hotp([]byte(rfcSecret), TOTPStep(now)-1)
+
+	if _, ok := ValidateTOTP(secret, previous, now, 0); ok {
+		t.Error("code of the previous step accepted without skew")
+	}
+	step, ok := ValidateTOTP(secret, previous, now, 1)
+	if !ok || step != TOTPStep(now)-1 {
+		t.Errorf("code of the previous step with skew 1 = (%d, %v), want (%d, true)", step, ok, TOTPStep(now)-1)
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte(rfcSecret))
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+	}{
+		{"wrong code", secret, "000000"},
+		{"short code", secret, "28708"},
+		{"eight digits", secret, "94287082"},
+		{"invalid secret", "not base32!", "287082"},
+	}
+	for _, tt := range tests {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, now, 1); ok {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+
+	// Secrets are accepted in lower case and codes with surrounding spaces.
+	if _, ok := ValidateTOTP(strings.ToLower(secret), " 287082 ", now, 0); !ok {
+		t.Error("lower-case secret or padded code rejected")
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Errorf("secret decodes to %d bytes, want 20", len(key))
+	}
+}
@@ -0,0 +1,278 @@
+package helpers
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limits on what ReadXLSXRows accepts, so a small crafted upload cannot make
+// it allocate without bound.
+const (
+	xlsxMaxRows             = 100000
+	xlsxMaxColumns          = 256
+	xlsxMaxUncompressedSize = 64 << 20
+)
+
+var ErrXLSXTooLarge = errors.New("xlsx: workbook exceeds the size limits")
+
+// XLSXWriter streams rows into a single-sheet XLSX workbook. Cells are
+// written as inline strings, so no shared string table has to be buffered.
+type XLSXWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// NewXLSXWriter writes the workbook skeleton to w and opens the sheet for rows.
+func NewXLSXWriter(w io.Writer, sheetName string) (*XLSXWriter, error) {
+	zw := zip.NewWriter(w)
+
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return nil, err
+	}
+
+	return &XLSXWriter{zw: zw, sheet: sheet}, nil
+}
+
+func (x *XLSXWriter) WriteRow(cells []string) error {
+	x.row++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, x.row)
+	for i, cell := range cells {
+		fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumnName(i), x.row)
+		if err := xml.EscapeText(&b, []byte(cell)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+
+	_, err := io.WriteString(x.sheet, b.String())
+	return err
+}
+
+// Close finishes the sheet and the zip archive. It does not close the underlying writer.
+func (x *XLSXWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+type xlsxSheetXML struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxRichText struct {
+	Text []string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	s := strings.Join(t.Text, "")
+	for _, run := range t.Runs {
+		s += run.Text
+	}
+	return s
+}
+
+// ReadXLSXRows returns the cell text of the first worksheet. The result is
+// indexed by row number minus one; rows missing from the sheet are nil.
+// Workbooks with more than xlsxMaxRows rows or xlsxMaxColumns columns, or
+// whose sheet and shared strings decompress to more than
+// xlsxMaxUncompressedSize bytes, are rejected with ErrXLSXTooLarge.
+func ReadXLSXRows(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	var sheets []string
+	for _, f := range zr.File {
+		files[f.Name] = f
+		if dir, _ := path.Split(f.Name); dir == "xl/worksheets/" && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f.Name)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("xlsx: workbook has no worksheets")
+	}
+	sort.Strings(sheets)
+	sheetName := sheets[0]
+	if _, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		sheetName = "xl/worksheets/sheet1.xml"
+	}
+
+	budget := int64(xlsxMaxUncompressedSize)
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxRichText `xml:"si"`
+		}
+		if err := decodeZipXML(f, &sst, &budget); err != nil {
+			return nil, err
+		}
+		for _, item := range sst.Items {
+			shared = append(shared, item.String())
+		}
+	}
+
+	var sheet xlsxSheetXML
+	if err := decodeZipXML(files[sheetName], &sheet, &budget); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for i, row := range sheet.Rows {
+		rowNumber := row.R
+		if rowNumber == 0 {
+			rowNumber = i + 1
+		}
+		if rowNumber < 0 {
+			return nil, fmt.Errorf("xlsx: invalid row number %d", rowNumber)
+		}
+		if rowNumber > xlsxMaxRows {
+			return nil, ErrXLSXTooLarge
+		}
+		for len(rows) < rowNumber {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for j, cell := range row.Cells {
+			column := j
+			if cell.Ref != "" {
+				column = xlsxColumnIndex(cell.Ref)
+				if column < 0 {
+					return nil, fmt.Errorf("xlsx: invalid cell reference %q", cell.Ref)
+				}
+			}
+			if column >= xlsxMaxColumns {
+				return nil, ErrXLSXTooLarge
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared) {
+					return nil, fmt.Errorf("xlsx: invalid shared string in cell %s", cell.Ref)
+				}
+				cells[column] = shared[index]
+			case "inlineStr":
+				cells[column] = cell.Inline.String()
+			default:
+				cells[column] = cell.Value
+			}
+		}
+		rows[rowNumber-1] = cells
+	}
+
+	return rows, nil
+}
+
+// decodeZipXML decodes an archived XML part, reading at most *budget
+// decompressed bytes and taking what it read off the budget.
+func decodeZipXML(f *zip.File, v interface{}, budget *int64) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	limited := &io.LimitedReader{R: rc, N: *budget + 1}
+	err = xml.NewDecoder(limited).Decode(v)
+	if limited.N <= 0 {
+		return ErrXLSXTooLarge
+	}
+	*budget = limited.N - 1
+	return err
+}
+
+// xlsxColumnName converts a zero-based column index to its letters (0 → A, 26 → AA).
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxColumnIndex extracts the zero-based column index from a cell reference
+// like "AB12". It returns -1 when the reference has no column letters, and
+// xlsxMaxColumns for columns at or beyond the limit.
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+		if index > xlsxMaxColumns {
+			return xlsxMaxColumns
+		}
+	}
+	return index - 1
+}
@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildXLSX zips a minimal workbook around the given worksheet XML.
+func buildXLSX(t *testing.T, sheet func(w io.Writer) error) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sheet(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sheetXML(rows string) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, xlsxSheetStart+rows+xlsxSheetEnd)
+		return err
+	}
+}
+
+func readXLSX(body []byte) ([][]string, error) {
+	return ReadXLSXRows(bytes.NewReader(body), int64(len(body)))
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	wide := make([]string, 30)
+	for i := range wide {
+		wide[i] = xlsxColumnName(i)
+	}
+	want := [][]string{
+		{"id", "question", "answer"},
+		{"1", "Ships <abroad> & back?", "Yes, \"always\".\nSecond line"},
+		{"2", "", "   leading and trailing spaces   "},
+		{"3", "هل تشحنون؟", "نعم"},
+		wide,
+	}
+
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf, "FAQs & more")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range want {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readXLSX(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestReadXLSXRowsSharedStringsAndGaps(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>first</t></si><si><r><t>rich </t></r><r><t>text</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": xlsxSheetStart +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+			`<row r="3"><c r="B3"><v>42</v></c></row>` +
+			xlsxSheetEnd,
+	}
+	for name, body := range parts {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(f, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readXLSX(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"first", "", "rich text"}, nil, {"", "42"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReadXLSXRowsRejectsMalformedSheets(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    string
+		tooLong bool
+	}{
+		{"cell ref without column", `<row r="1"><c r="12"><v>x</v></c></row>`, false},
+		{"negative row", `<row r="-3"><c r="A1"><v>x</v></c></row>`, false},
+		{"shared string out of range", `<row r="1"><c r="A1" t="s"><v>7</v></c></row>`, false},
+		{"row beyond limit", `<row r="100000000"><c r="A1"><v>x</v></c></row>`, true},
+		{"column beyond limit", `<row r="1"><c r="XFD1"><v>x</v></c></row>`, true},
+		{"overflowing column letters", `<row r="1"><c r="ZZZZZZZZZZZZZZZZZZZZ1"><v>x</v></c></row>`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readXLSX(buildXLSX(t, sheetXML(tt.rows)))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.tooLong != errors.Is(err, ErrXLSXTooLarge) {
+				t.Errorf("error %v, ErrXLSXTooLarge expected: %v", err, tt.tooLong)
+			}
+		})
+	}
+}
+
+func TestReadXLSXRowsRejectsDecompressionBombs(t *testing.T) {
+	body := buildXLSX(t, func(w io.Writer) error {
+		if _, err := io.WriteString(w, xlsxSheetStart+`<row r="1"><c r="A1" t="inlineStr"><is><t>`); err != nil {
+			return err
+		}
+		chunk := strings.Repeat("a", 1<<20)
+		for written := 0; written <= xlsxMaxUncompressedSize; written += len(chunk) {
+			if _, err := io.WriteString(w, chunk); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, `</t></is></c></row>`+xlsxSheetEnd)
+		return err
+	})
+
+	if _, err := readXLSX(body); !errors.Is(err, ErrXLSXTooLarge) {
+		t.Errorf("got %v, want ErrXLSXTooLarge", err)
+	}
+}
+
+func TestXLSXColumnNames(t *testing.T) {
+	tests := []struct {
+		index int
+		name  string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{255, "IV"},
+	}
+	for _, tt := range tests {
+		if got := xlsxColumnName(tt.index); got != tt.name {
+			t.Errorf("xlsxColumnName(%d) = %s, want %s", tt.index, got, tt.name)
+		}
+		if got := xlsxColumnIndex(tt.name + "7"); got != tt.index {
+			t.Errorf("xlsxColumnIndex(%s7) = %d, want %d", tt.name, got, tt.index)
+		}
+	}
+	if got := xlsxColumnIndex("7"); got != -1 {
+		t.Errorf("xlsxColumnIndex(7) = %d, want -1", got)
+	}
+}
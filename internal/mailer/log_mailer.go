@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/kareemhamed001/faq/internal/logger"
+)
+
+// LogMailer writes emails to the application log instead of sending them. Intended for local use.
+type LogMailer struct {
+	from   string
+	logger *logger.Logger
+}
+
+func NewLogMailer(from string, logr *logger.Logger) *LogMailer {
+	return &LogMailer{from: from, logger: logr}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Infow("outgoing email",
+		"from", m.from,
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Body,
+	)
+	return nil
+}
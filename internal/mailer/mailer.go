@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kareemhamed001/faq/internal/logger"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing emails. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New builds the mailer selected by driver ("log" or "file").
+func New(driver, from, fileDir string, logr *logger.Logger) (Mailer, error) {
+	switch driver {
+	case "", "log":
+		return NewLogMailer(from, logr), nil
+	case "file":
+		return NewFileMailer(from, fileDir)
+	default:
+		return nil, fmt.Errorf("unsupported MAILER_DRIVER: %s", driver)
+	}
+}
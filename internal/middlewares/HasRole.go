@@ -5,13 +5,27 @@ import (
 
 	"github.com/gin-gonic/gin"
 	appErrors "github.com/kareemhamed001/faq/internal/errors"
-	"github.com/kareemhamed001/faq/internal/helpers"
 	"github.com/kareemhamed001/faq/internal/responses"
 	"github.com/kareemhamed001/faq/internal/types"
 )
 
-func HasRole(roles []types.UserRole, jwtSecret string) gin.HandlerFunc {
+func HasRole(roles []types.UserRole, tokens TokenValidator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if scope, ok := apiKeyScopeFromContext(ctx); ok {
+			if !containsRole(roles, types.RoleMerchant) {
+				responses.WriteError(ctx, appErrors.ErrForbidden.Status, appErrors.ErrForbidden.Code, "access forbidden")
+				ctx.Abort()
+				return
+			}
+			if scope != types.APIKeyScopeReadWrite && !isReadOnlyMethod(ctx.Request.Method) {
+				responses.WriteError(ctx, appErrors.ErrForbidden.Status, appErrors.ErrForbidden.Code, "api key is read-only")
+				ctx.Abort()
+				return
+			}
+			ctx.Next()
+			return
+		}
+
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
 			responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, appErrors.ErrUnauthorized.Message)
@@ -26,7 +40,7 @@ func HasRole(roles []types.UserRole, jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := helpers.ValidateToken(tokenParts[1], jwtSecret)
+		claims, err := tokens.ValidateAccessToken(ctx.Request.Context(), tokenParts[1])
 		if err != nil {
 			responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, "invalid or expired token")
 			ctx.Abort()
@@ -50,6 +64,9 @@ func HasRole(roles []types.UserRole, jwtSecret string) gin.HandlerFunc {
 			ctx.Set("user_id", userID)
 		}
 		ctx.Set("role", role)
+		if sessionID, ok := claims["sid"].(string); ok {
+			ctx.Set("session_id", sessionID)
+		}
 
 		ctx.Next()
 	}
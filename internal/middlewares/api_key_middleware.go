@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/kareemhamed001/faq/internal/errors"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/responses"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+// APIKeyValidator resolves a raw X-API-Key header value to its key record.
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// APIKeyAuth authenticates requests carrying an X-API-Key header as the merchant
+// that owns the key. Requests without the header pass through untouched.
+// HasRole accepts the identity set here; AuthMiddleware (account management) does not.
+func APIKeyAuth(keys APIKeyValidator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rawKey := ctx.GetHeader("X-API-Key")
+		if rawKey == "" {
+			ctx.Next()
+			return
+		}
+
+		key, err := keys.ValidateAPIKey(ctx.Request.Context(), rawKey)
+		if err != nil {
+			responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, "invalid or expired api key")
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("user_id", uint64(key.UserID))
+		ctx.Set("role", string(types.RoleMerchant))
+		ctx.Set("api_key_id", key.ID)
+		ctx.Set("api_key_scope", key.Scope)
+
+		ctx.Next()
+	}
+}
+
+// apiKeyScopeFromContext returns the scope of the API key that authenticated the request, if any.
+func apiKeyScopeFromContext(ctx *gin.Context) (types.APIKeyScope, bool) {
+	raw, exists := ctx.Get("api_key_scope")
+	if !exists {
+		return "", false
+	}
+	scope, ok := raw.(types.APIKeyScope)
+	return scope, ok
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -1,16 +1,21 @@
 package middlewares
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	appErrors "github.com/kareemhamed001/faq/internal/errors"
-	"github.com/kareemhamed001/faq/internal/helpers"
 	"github.com/kareemhamed001/faq/internal/responses"
 )
 
+// TokenValidator validates an access token (signature, type and session state) and returns its claims.
+type TokenValidator interface {
+	ValidateAccessToken(ctx context.Context, token string) (map[string]interface{}, error)
+}
+
 // AuthMiddleware validates JWT token for any authenticated user and populates context claims.
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+func AuthMiddleware(tokens TokenValidator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -26,7 +31,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := helpers.ValidateToken(tokenParts[1], jwtSecret)
+		claims, err := tokens.ValidateAccessToken(ctx.Request.Context(), tokenParts[1])
 		if err != nil {
 			responses.WriteError(ctx, appErrors.ErrUnauthorized.Status, appErrors.ErrUnauthorized.Code, "invalid or expired token")
 			ctx.Abort()
@@ -39,6 +44,9 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		if userID, ok := extractUserIDFromClaims(claims); ok {
 			ctx.Set("user_id", userID)
 		}
+		if sessionID, ok := claims["sid"].(string); ok {
+			ctx.Set("session_id", sessionID)
+		}
 
 		ctx.Next()
 	}
@@ -5,11 +5,16 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/kareemhamed001/faq/internal/helpers"
 )
 
-func SetUserData(jwtSecret string) gin.HandlerFunc {
+func SetUserData(tokens TokenValidator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		// Identity was already established from an API key.
+		if _, ok := apiKeyScopeFromContext(ctx); ok {
+			ctx.Next()
+			return
+		}
+
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
 			ctx.Next()
@@ -22,7 +27,7 @@ func SetUserData(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := helpers.ValidateToken(tokenParts[1], jwtSecret)
+		claims, err := tokens.ValidateAccessToken(ctx.Request.Context(), tokenParts[1])
 		if err != nil {
 			ctx.Next()
 			return
@@ -38,6 +43,9 @@ func SetUserData(jwtSecret string) gin.HandlerFunc {
 			ctx.Set("user_id", userID)
 		}
 		ctx.Set("role", role)
+		if sessionID, ok := claims["sid"].(string); ok {
+			ctx.Set("session_id", sessionID)
+		}
 
 		ctx.Next()
 	}
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP,
+    ADD COLUMN verification_sent_at TIMESTAMP;
+
+-- Accounts created before verification existed are treated as verified.
+UPDATE users SET email_verified_at = created_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN email_verified_at,
+    DROP COLUMN verification_sent_at;
+-- +goose StatementEnd
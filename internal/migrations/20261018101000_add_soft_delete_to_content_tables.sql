@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Users were inserted with a zero deleted_at while it was a plain timestamp.
+UPDATE users SET deleted_at = NULL WHERE deleted_at < '1900-01-01';
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
+
+ALTER TABLE stores ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE faqs ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE translations ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_stores_deleted_at ON stores(deleted_at);
+CREATE INDEX idx_categories_deleted_at ON categories(deleted_at);
+CREATE INDEX idx_faqs_deleted_at ON faqs(deleted_at);
+CREATE INDEX idx_translations_deleted_at ON translations(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE translations DROP COLUMN deleted_at;
+ALTER TABLE faqs DROP COLUMN deleted_at;
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE stores DROP COLUMN deleted_at;
+DROP INDEX idx_users_deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE faq_revisions (
+    id SERIAL PRIMARY KEY,
+    faq_id INT NOT NULL REFERENCES faqs(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    author_id INT REFERENCES users(id) ON DELETE SET NULL,
+    category_id INT NOT NULL,
+    translations JSONB NOT NULL DEFAULT '[]',
+    restored_from INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (faq_id, revision)
+);
+
+-- Existing FAQs start their history with their current content.
+INSERT INTO faq_revisions (faq_id, revision, action, category_id, translations)
+SELECT f.id, 1, 'created', COALESCE(f.category_id, 0), COALESCE((
+    SELECT jsonb_agg(jsonb_build_object('language', t.language, 'question', t.question, 'answer', t.answer) ORDER BY t.language)
+    FROM translations t
+    WHERE t.faq_id = f.id AND t.deleted_at IS NULL
+), '[]')
+FROM faqs f
+WHERE f.deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE faq_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing FAQs were already public, so they start out published.
+ALTER TABLE faqs ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE faqs ALTER COLUMN status SET DEFAULT 'draft';
+CREATE INDEX idx_faqs_status ON faqs(status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE faqs DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE faqs ADD COLUMN publish_at TIMESTAMP;
+ALTER TABLE faqs ADD COLUMN expire_at TIMESTAMP;
+CREATE INDEX idx_faqs_publish_at ON faqs(publish_at);
+CREATE INDEX idx_faqs_expire_at ON faqs(expire_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE faqs DROP COLUMN expire_at;
+ALTER TABLE faqs DROP COLUMN publish_at;
+-- +goose StatementEnd
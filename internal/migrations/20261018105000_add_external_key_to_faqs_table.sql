@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE faqs ADD COLUMN external_key VARCHAR(255);
+
+-- External keys are unique per store, with global FAQs sharing one namespace.
+CREATE UNIQUE INDEX idx_faqs_external_key ON faqs (COALESCE(store_id, 0), external_key)
+    WHERE external_key IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_faqs_external_key;
+ALTER TABLE faqs DROP COLUMN external_key;
+-- +goose StatementEnd
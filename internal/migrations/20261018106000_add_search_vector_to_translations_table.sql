@@ -0,0 +1,78 @@
+-- +goose Up
+-- +goose StatementBegin
+-- faq_ts_config maps a translation's language code to its text search
+-- configuration. Unknown languages are indexed without stemming.
+CREATE FUNCTION faq_ts_config(language TEXT) RETURNS regconfig AS $$
+    SELECT CASE lower(split_part(language, '-', 1))
+        WHEN 'ar' THEN 'arabic'
+        WHEN 'da' THEN 'danish'
+        WHEN 'de' THEN 'german'
+        WHEN 'el' THEN 'greek'
+        WHEN 'en' THEN 'english'
+        WHEN 'es' THEN 'spanish'
+        WHEN 'fi' THEN 'finnish'
+        WHEN 'fr' THEN 'french'
+        WHEN 'ga' THEN 'irish'
+        WHEN 'hu' THEN 'hungarian'
+        WHEN 'id' THEN 'indonesian'
+        WHEN 'it' THEN 'italian'
+        WHEN 'lt' THEN 'lithuanian'
+        WHEN 'ne' THEN 'nepali'
+        WHEN 'nl' THEN 'dutch'
+        WHEN 'no' THEN 'norwegian'
+        WHEN 'nb' THEN 'norwegian'
+        WHEN 'pt' THEN 'portuguese'
+        WHEN 'ro' THEN 'romanian'
+        WHEN 'ru' THEN 'russian'
+        WHEN 'sv' THEN 'swedish'
+        WHEN 'ta' THEN 'tamil'
+        WHEN 'tr' THEN 'turkish'
+        ELSE 'simple'
+    END::regconfig
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- faq_tsquery parses a search in every configuration faq_ts_config can
+-- return and ORs the results, so a single query matches translations stemmed
+-- in any language and can still use the GIN index.
+CREATE FUNCTION faq_tsquery(query TEXT) RETURNS tsquery AS $$
+    SELECT websearch_to_tsquery('simple', query)
+        || websearch_to_tsquery('arabic', query)
+        || websearch_to_tsquery('danish', query)
+        || websearch_to_tsquery('german', query)
+        || websearch_to_tsquery('greek', query)
+        || websearch_to_tsquery('english', query)
+        || websearch_to_tsquery('spanish', query)
+        || websearch_to_tsquery('finnish', query)
+        || websearch_to_tsquery('french', query)
+        || websearch_to_tsquery('irish', query)
+        || websearch_to_tsquery('hungarian', query)
+        || websearch_to_tsquery('indonesian', query)
+        || websearch_to_tsquery('italian', query)
+        || websearch_to_tsquery('lithuanian', query)
+        || websearch_to_tsquery('nepali', query)
+        || websearch_to_tsquery('dutch', query)
+        || websearch_to_tsquery('norwegian', query)
+        || websearch_to_tsquery('portuguese', query)
+        || websearch_to_tsquery('romanian', query)
+        || websearch_to_tsquery('russian', query)
+        || websearch_to_tsquery('swedish', query)
+        || websearch_to_tsquery('tamil', query)
+        || websearch_to_tsquery('turkish', query)
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Questions weigh more than answers when ranking.
+ALTER TABLE translations ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(faq_ts_config(language), coalesce(question, '')), 'A') ||
+    setweight(to_tsvector(faq_ts_config(language), coalesce(answer, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_translations_search_vector ON translations USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_translations_search_vector;
+ALTER TABLE translations DROP COLUMN search_vector;
+DROP FUNCTION faq_tsquery(TEXT);
+DROP FUNCTION faq_ts_config(TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Serves the word similarity operator (<%) used by fuzzy search and suggestions.
+CREATE INDEX idx_translations_question_trgm ON translations USING GIN (question gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_translations_question_trgm;
+-- +goose StatementEnd
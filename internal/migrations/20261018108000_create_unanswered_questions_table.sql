@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE unanswered_questions (
+    id SERIAL PRIMARY KEY,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    language VARCHAR(10) NOT NULL,
+    question TEXT NOT NULL,
+    best_faq_id INT REFERENCES faqs(id) ON DELETE SET NULL,
+    best_confidence DOUBLE PRECISION,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_unanswered_questions_store_created ON unanswered_questions (store_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE unanswered_questions;
+-- +goose StatementEnd
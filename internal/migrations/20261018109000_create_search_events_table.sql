@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE search_events (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    store_id INT REFERENCES stores(id) ON DELETE CASCADE,
+    query TEXT NOT NULL,
+    normalized_query TEXT NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    result_count INT NOT NULL,
+    clicked_faq_id INT REFERENCES faqs(id) ON DELETE SET NULL,
+    clicked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_search_events_created ON search_events (created_at);
+CREATE INDEX idx_search_events_store_created ON search_events (store_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE search_events;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE faq_feedbacks (
+    id SERIAL PRIMARY KEY,
+    faq_id INT NOT NULL REFERENCES faqs(id) ON DELETE CASCADE,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    client_hash VARCHAR(64) NOT NULL,
+    helpful BOOLEAN NOT NULL,
+    comment TEXT,
+    language VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (faq_id, store_id, client_hash)
+);
+
+CREATE INDEX idx_faq_feedbacks_store_faq ON faq_feedbacks (store_id, faq_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE faq_feedbacks;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE faq_view_counts (
+    faq_id INT NOT NULL REFERENCES faqs(id) ON DELETE CASCADE,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (faq_id, store_id)
+);
+
+CREATE INDEX idx_faq_view_counts_store_views ON faq_view_counts (store_id, views DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE faq_view_counts;
+-- +goose StatementEnd
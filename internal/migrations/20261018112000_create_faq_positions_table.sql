@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE faq_positions (
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    faq_id INT NOT NULL REFERENCES faqs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (store_id, faq_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE faq_positions;
+-- +goose StatementEnd
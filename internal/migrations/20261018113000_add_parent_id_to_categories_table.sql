@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE categories ADD COLUMN parent_id INT REFERENCES categories(id);
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE categories DROP COLUMN parent_id;
+-- +goose StatementEnd
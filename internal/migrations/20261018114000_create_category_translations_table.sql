@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE category_translations (
+    id SERIAL PRIMARY KEY,
+    category_id INT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    language VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    UNIQUE (category_id, language)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE category_translations;
+-- +goose StatementEnd
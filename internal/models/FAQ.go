@@ -1,11 +1,27 @@
 package models
 
+import (
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
 type FAQ struct {
-	ID           uint          `gorm:"primaryKey" json:"id"`
-	CategoryID   uint          `json:"category_id"`
-	Category     Category      `json:"category"`
-	StoreID      *uint         `json:"store_id"` // Nullable if its global
-	IsGlobal     bool          `json:"is_global"`
-	Translations []Translation `json:"translations"`
-	Store        *Store        `json:"store,omitempty"`
+	ID           uint                `gorm:"primaryKey" json:"id"`
+	CategoryID   uint                `json:"category_id"`
+	Category     Category            `json:"category"`
+	StoreID      *uint               `json:"store_id"` // Nullable if its global
+	IsGlobal     bool                `json:"is_global"`
+	ExternalKey  *string             `json:"external_key"` // Caller-chosen key used by imports to upsert
+	Status       types.FAQStatus     `gorm:"type:varchar(20);not null;default:draft" json:"status"`
+	PublishAt    *time.Time          `json:"publish_at"`
+	ExpireAt     *time.Time          `json:"expire_at"`
+	Translations []Translation       `json:"translations"`
+	Store        *Store              `json:"store,omitempty"`
+	DeletedAt    gorm.DeletedAt      `gorm:"index" json:"-"`
+	SearchRank   float64             `gorm:"->;-:migration" json:"search_rank,omitempty"` // Relevance, only set by searches
+	Position     *int                `gorm:"->;-:migration" json:"position,omitempty"`    // Only set on store pages
+	Views        int64               `gorm:"->;-:migration" json:"views,omitempty"`       // Only set when ordering by popularity
+	Feedback     *FAQFeedbackSummary `gorm:"-" json:"feedback,omitempty"`                 // Only set for merchants and admins
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+// APIKey is a store-scoped credential for server-to-server access. Only a hash
+// of the key is stored; Prefix is kept so merchants can tell keys apart.
+type APIKey struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	StoreID    uint              `json:"store_id"`
+	UserID     uint              `json:"user_id"`
+	Name       string            `json:"name"`
+	Prefix     string            `json:"prefix"`
+	KeyHash    string            `gorm:"uniqueIndex" json:"-"`
+	Scope      types.APIKeyScope `gorm:"type:varchar(20);not null" json:"scope"`
+	LastUsedAt *time.Time        `json:"last_used_at"`
+	ExpiresAt  *time.Time        `json:"expires_at"`
+	RevokedAt  *time.Time        `json:"revoked_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
@@ -1,7 +1,13 @@
 package models
 
+import "gorm.io/gorm"
+
 type Category struct {
-	ID   uint   `gorm:"primaryKey" json:"id"`
-	Name string `json:"name"`
-	FAQs []FAQ  `json:"faqs,omitempty"`
+	ID           uint                  `gorm:"primaryKey" json:"id"`
+	Name         string                `json:"name"`
+	ParentID     *uint                 `json:"parent_id"` // Nullable for top-level categories
+	Children     []Category            `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Translations []CategoryTranslation `json:"translations,omitempty"`
+	FAQs         []FAQ                 `json:"faqs,omitempty"`
+	DeletedAt    gorm.DeletedAt        `gorm:"index" json:"-"`
 }
@@ -0,0 +1,8 @@
+package models
+
+type CategoryTranslation struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	CategoryID uint   `json:"category_id"`
+	Language   string `json:"language"`
+	Name       string `json:"name"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// FAQFeedback is one customer's vote on whether an FAQ helped, given on a
+// store's page. A client votes once per FAQ and store; voting again replaces
+// the earlier vote.
+type FAQFeedback struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	FAQID      uint      `json:"faq_id"`
+	StoreID    uint      `json:"store_id"`
+	ClientHash string    `json:"-"`
+	Helpful    bool      `json:"helpful"`
+	Comment    *string   `json:"comment"`
+	Language   string    `json:"language"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// FAQFeedbackSummary aggregates the votes on an FAQ. Helpfulness is the share
+// of helpful votes, zero while there are none.
+type FAQFeedbackSummary struct {
+	Helpful     int64   `json:"helpful"`
+	NotHelpful  int64   `json:"not_helpful"`
+	Helpfulness float64 `json:"helpfulness"`
+}
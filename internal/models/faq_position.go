@@ -0,0 +1,9 @@
+package models
+
+// FAQPosition is where a store shows an FAQ within its category, counting up
+// from 1. Global FAQs can have a different position in every store.
+type FAQPosition struct {
+	StoreID  uint `gorm:"primaryKey" json:"store_id"`
+	FAQID    uint `gorm:"primaryKey" json:"faq_id"`
+	Position int  `json:"position"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+// FAQRevision is an immutable snapshot of an FAQ taken after every change.
+// Revision numbers count up from 1 per FAQ.
+type FAQRevision struct {
+	ID           uint                    `gorm:"primaryKey" json:"id"`
+	FAQID        uint                    `json:"faq_id"`
+	Revision     int                     `json:"revision"`
+	Action       types.FAQRevisionAction `gorm:"type:varchar(20);not null" json:"action"`
+	AuthorID     *uint                   `json:"author_id"`
+	CategoryID   uint                    `json:"category_id"`
+	Translations RevisionTranslations    `gorm:"type:jsonb" json:"translations"`
+	RestoredFrom *int                    `json:"restored_from,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+}
+
+type RevisionTranslation struct {
+	Language string `json:"language"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// RevisionTranslations is the full translation set of a revision, stored as JSON.
+type RevisionTranslations []RevisionTranslation
+
+func (t RevisionTranslations) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (t *RevisionTranslations) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*t = nil
+		return nil
+	default:
+		return errors.New("unsupported type for revision translations")
+	}
+	return json.Unmarshal(b, t)
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LoginThrottle tracks consecutive failed logins for one key, either an
+// account ("account:<email>") or a client address ("ip:<addr>").
+type LoginThrottle struct {
+	Key          string     `gorm:"primaryKey" json:"key"`
+	Failures     int        `json:"failures"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// MFARecoveryCode is a single-use fallback for a lost authenticator.
+type MFARecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RefreshToken is a single link in a rotating refresh token chain.
+// All tokens issued from one login share a FamilyID, which doubles as the
+// session id embedded in access tokens.
+type RefreshToken struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `json:"user_id"`
+	FamilyID     string     `gorm:"index" json:"family_id"`
+	TokenHash    string     `gorm:"uniqueIndex" json:"-"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *uint      `json:"replaced_by_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+// SearchEvent is one search as performed by a user or customer. StoreID is
+// nil for searches across every store, such as an admin's FAQ search.
+type SearchEvent struct {
+	ID              uint               `gorm:"primaryKey" json:"id"`
+	Source          types.SearchSource `json:"source"`
+	StoreID         *uint              `json:"store_id"`
+	Query           string             `json:"query"`
+	NormalizedQuery string             `json:"-"`
+	Language        string             `json:"language"`
+	ResultCount     int                `json:"result_count"`
+	ClickedFAQID    *uint              `json:"clicked_faq_id"`
+	ClickedAt       *time.Time         `json:"clicked_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Setting is a runtime-configurable key/value pair managed by admins.
+type Setting struct {
+	Key       string    `gorm:"primaryKey" json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
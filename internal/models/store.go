@@ -1,11 +1,16 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Store struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	Name       string    `json:"name"`
-	MerchantID uint      `json:"merchant_id"` // FK to Users table
-	CreatedAt  time.Time `json:"created_at"`
-	FAQs       []FAQ     `gorm:"foreignKey:StoreID" json:"faqs,omitempty"`
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Name       string         `json:"name"`
+	MerchantID uint           `json:"merchant_id"` // FK to Users table
+	CreatedAt  time.Time      `json:"created_at"`
+	FAQs       []FAQ          `gorm:"foreignKey:StoreID" json:"faqs,omitempty"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 }
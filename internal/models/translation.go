@@ -1,9 +1,13 @@
 package models
 
+import "gorm.io/gorm"
+
 type Translation struct {
-	ID       uint   `gorm:"primaryKey" json:"id"`
-	FAQID    uint   `json:"faq_id"`
-	Language string `json:"language"`
-	Question string `json:"question"`
-	Answer   string `json:"answer"`
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	FAQID     uint           `json:"faq_id"`
+	Language  string         `json:"language"`
+	Question  string         `json:"question"`
+	Answer    string         `json:"answer"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Snippet   string         `gorm:"->;-:migration" json:"snippet,omitempty"` // Highlighted answer excerpt, only set by searches
 }
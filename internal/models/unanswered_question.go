@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UnansweredQuestion is a customer question that no FAQ of the store answered
+// with enough confidence. BestFAQID is the closest match, if there was any.
+type UnansweredQuestion struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	StoreID        uint      `json:"store_id"`
+	Language       string    `json:"language"`
+	Question       string    `json:"question"`
+	BestFAQID      *uint     `json:"best_faq_id"`
+	BestConfidence *float64  `json:"best_confidence"`
+	CreatedAt      time.Time `json:"created_at"`
+}
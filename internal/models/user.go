@@ -4,16 +4,27 @@ import (
 	"time"
 
 	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"type:varchar(255);not null" json:"name"`
-	Email     string         `gorm:"uniqueIndex" json:"email"`
-	Password  string         `json:"-"`
-	Role      types.UserRole `gorm:"type:varchar(20);not null" json:"role"`
-	Store     *Store         `gorm:"foreignKey:MerchantID" json:"store,omitempty"`
+	ID       uint           `gorm:"primaryKey" json:"id"`
+	Name     string         `gorm:"type:varchar(255);not null" json:"name"`
+	Email    string         `gorm:"uniqueIndex" json:"email"`
+	Password string         `json:"-"`
+	Role     types.UserRole `gorm:"type:varchar(20);not null" json:"role"`
+	Store    *Store         `gorm:"foreignKey:MerchantID" json:"store,omitempty"`
+
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+	VerificationSentAt *time.Time `json:"-"`
+
+	TOTPSecret    string     `gorm:"column:totp_secret" json:"-"`
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at" json:"totp_enabled_at"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step" json:"-"`
+
+	SuspendedAt *time.Time `json:"suspended_at"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt time.Time      `gorm:"index" json:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -0,0 +1,6 @@
+package requests
+
+// ChangeRoleRequest defines payload for changing a user's role.
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin merchant customer"`
+}
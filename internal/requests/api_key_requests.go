@@ -0,0 +1,10 @@
+package requests
+
+import "time"
+
+// CreateAPIKeyRequest defines payload for issuing a store API key.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=255"`
+	Scope     string     `json:"scope" binding:"required,oneof=read read_write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,8 @@
+package requests
+
+// AskRequest carries a customer's free-text question to a store.
+type AskRequest struct {
+	Question string `json:"question" binding:"required,max=1000"`
+	Language string `json:"language" binding:"max=10"`
+	Limit    int    `json:"limit"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+// FAQFeedbackRequest is a customer's "was this helpful?" vote. ClientID is an
+// optional stable id kept by the client (for example in local storage) so a
+// customer can change their vote from another network.
+type FAQFeedbackRequest struct {
+	Helpful  *bool  `json:"helpful" binding:"required"`
+	Comment  string `json:"comment" binding:"max=2000"`
+	Language string `json:"language" binding:"max=10"`
+	ClientID string `json:"client_id" binding:"max=255"`
+}
@@ -0,0 +1,12 @@
+package requests
+
+// UpdateProfileRequest defines payload for PATCH /api/me; omitted fields stay unchanged.
+type UpdateProfileRequest struct {
+	Name  *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Email *string `json:"email" binding:"omitempty,email"`
+}
+
+// CloseAccountRequest confirms account closure with the current password.
+type CloseAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
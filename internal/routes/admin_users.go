@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+func SetupAdminUserRoutes(router *gin.Engine, adminUserHandler handlers.AdminUserHandler, tokens middlewares.TokenValidator) {
+	users := router.Group("/api/admin/users", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens))
+
+	users.GET("/", adminUserHandler.ListUsers)
+	users.GET("/:id", adminUserHandler.GetUser)
+	users.PUT("/:id/role", adminUserHandler.ChangeRole)
+	users.POST("/:id/suspend", adminUserHandler.SuspendUser)
+	users.POST("/:id/reactivate", adminUserHandler.ReactivateUser)
+	users.POST("/:id/unlock", adminUserHandler.UnlockUser)
+	users.DELETE("/:id", adminUserHandler.DeleteUser)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+)
+
+// SetupAPIKeyRoutes registers key management. It requires a bearer token so an
+// API key can never be used to mint or revoke other keys.
+func SetupAPIKeyRoutes(router *gin.Engine, apiKeyHandler handlers.APIKeyHandler, tokens middlewares.TokenValidator) {
+	apiKeys := router.Group("/api/api-keys", middlewares.AuthMiddleware(tokens))
+
+	apiKeys.GET("/", apiKeyHandler.ListAPIKeys)
+	apiKeys.POST("/", apiKeyHandler.CreateAPIKey)
+	apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+// SetupAskRoutes registers the public ask endpoint and the unanswered question
+// log for merchants and admins.
+func SetupAskRoutes(router *gin.Engine, askHandler handlers.AskHandler, tokens middlewares.TokenValidator) {
+	router.POST("/api/stores/:id/ask", askHandler.Ask)
+
+	unanswered := router.Group("/api/unanswered-questions", middlewares.HasRole([]types.UserRole{types.RoleAdmin, types.RoleMerchant}, tokens))
+	unanswered.GET("/", askHandler.ListUnansweredQuestions)
+}
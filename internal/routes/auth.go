@@ -3,11 +3,17 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
 )
 
-func SetupAuthRoutes(router *gin.Engine, authHandler handlers.AuthHandler) {
+func SetupAuthRoutes(router *gin.Engine, authHandler handlers.AuthHandler, tokens middlewares.TokenValidator) {
 	auth := router.Group("/auth")
 
 	auth.POST("/register", authHandler.Register)
 	auth.POST("/login", authHandler.Login)
+	auth.POST("/refresh", authHandler.Refresh)
+	auth.POST("/logout", authHandler.Logout)
+	auth.POST("/logout-all", middlewares.AuthMiddleware(tokens), authHandler.LogoutAll)
+	auth.POST("/verify-email", authHandler.VerifyEmail)
+	auth.POST("/verify-email/resend", middlewares.AuthMiddleware(tokens), authHandler.ResendVerification)
 }
@@ -7,13 +7,29 @@ import (
 	"github.com/kareemhamed001/faq/internal/types"
 )
 
-func SetupFaqRoutes(router *gin.Engine, faqHandler handlers.FAQHandler, jwtSecret string) {
-	auth := router.Group("/api", middlewares.HasRole([]types.UserRole{types.RoleAdmin, types.RoleMerchant}, jwtSecret))
+func SetupFaqRoutes(router *gin.Engine, faqHandler handlers.FAQHandler, tokens middlewares.TokenValidator) {
+	router.POST("/api/stores/:id/faqs/:faqId/feedback", faqHandler.SubmitFeedback)
+
+	auth := router.Group("/api", middlewares.HasRole([]types.UserRole{types.RoleAdmin, types.RoleMerchant}, tokens))
 
 	faqCategories := auth.Group("/faqs")
 	faqCategories.GET("/", faqHandler.GetAllFAQs)
 	faqCategories.GET("/:id", faqHandler.GetFAQByID)
 	faqCategories.POST("/", faqHandler.CreateFAQ)
+	faqCategories.GET("/export", faqHandler.ExportFAQs)
+	faqCategories.POST("/import", faqHandler.ImportFAQs)
+	faqCategories.GET("/lowest-rated", faqHandler.LowestRatedFAQs)
+	faqCategories.PUT("/reorder", faqHandler.ReorderFAQs)
 	faqCategories.PUT("/:id", faqHandler.UpdateFAQ)
 	faqCategories.DELETE("/:id", faqHandler.DeleteFAQ)
+	faqCategories.PUT("/:id/schedule", faqHandler.ScheduleFAQ)
+	faqCategories.POST("/:id/submit", faqHandler.SubmitFAQ)
+	faqCategories.POST("/:id/approve", faqHandler.ApproveFAQ)
+	faqCategories.POST("/:id/reject", faqHandler.RejectFAQ)
+	faqCategories.POST("/:id/archive", faqHandler.ArchiveFAQ)
+	faqCategories.POST("/:id/unarchive", faqHandler.UnarchiveFAQ)
+	faqCategories.GET("/:id/revisions", faqHandler.ListRevisions)
+	faqCategories.GET("/:id/revisions/:rev/diff", faqHandler.DiffRevisions)
+	faqCategories.POST("/:id/revisions/:rev/restore", faqHandler.RestoreRevision)
+	faqCategories.GET("/:id/feedback", faqHandler.ListFeedback)
 }
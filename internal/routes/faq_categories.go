@@ -7,13 +7,15 @@ import (
 	"github.com/kareemhamed001/faq/internal/types"
 )
 
-func SetupFaqCategoriesRoutes(router *gin.Engine, faqCategoryHandler handlers.FAQCategoryHandler, jwtSecret string) {
+func SetupFaqCategoriesRoutes(router *gin.Engine, faqCategoryHandler handlers.FAQCategoryHandler, tokens middlewares.TokenValidator) {
 	auth := router.Group("/api")
 
 	faqCategories := auth.Group("/faq-categories")
-	faqCategories.GET("/", middlewares.HasRole([]types.UserRole{types.RoleAdmin, types.RoleMerchant}, jwtSecret), faqCategoryHandler.GetAllCategories)
-	faqCategories.GET("/:id", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, jwtSecret), faqCategoryHandler.GetCategoryByID)
-	faqCategories.POST("/", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, jwtSecret), faqCategoryHandler.CreateCategory)
-	faqCategories.PUT("/:id", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, jwtSecret), faqCategoryHandler.UpdateCategory)
-	faqCategories.DELETE("/:id", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, jwtSecret), faqCategoryHandler.DeleteCategory)
+	faqCategories.GET("/", middlewares.HasRole([]types.UserRole{types.RoleAdmin, types.RoleMerchant}, tokens), faqCategoryHandler.GetAllCategories)
+	faqCategories.GET("/tree", middlewares.HasRole([]types.UserRole{types.RoleAdmin, types.RoleMerchant}, tokens), faqCategoryHandler.GetCategoryTree)
+	faqCategories.GET("/:id", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens), faqCategoryHandler.GetCategoryByID)
+	faqCategories.POST("/", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens), faqCategoryHandler.CreateCategory)
+	faqCategories.PUT("/:id", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens), faqCategoryHandler.UpdateCategory)
+	faqCategories.PUT("/:id/move", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens), faqCategoryHandler.MoveCategory)
+	faqCategories.DELETE("/:id", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens), faqCategoryHandler.DeleteCategory)
 }
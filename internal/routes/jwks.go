@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+)
+
+func SetupJWKSRoutes(router *gin.Engine, jwksHandler handlers.JWKSHandler) {
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+}
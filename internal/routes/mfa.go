@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+)
+
+func SetupMFARoutes(router *gin.Engine, mfaHandler handlers.MFAHandler, tokens middlewares.TokenValidator) {
+	// Second login step, authorized by the mfa_token returned from /auth/login.
+	login := router.Group("/auth/login/mfa")
+	login.POST("", mfaHandler.CompleteLogin)
+	login.POST("/setup", mfaHandler.BeginLoginEnrollment)
+	login.POST("/confirm", mfaHandler.CompleteLoginEnrollment)
+
+	mfa := router.Group("/auth/mfa", middlewares.AuthMiddleware(tokens))
+	mfa.POST("/totp/setup", mfaHandler.BeginEnrollment)
+	mfa.POST("/totp/confirm", mfaHandler.ConfirmEnrollment)
+	mfa.POST("/totp/disable", mfaHandler.Disable)
+	mfa.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+)
+
+func SetupPasswordRoutes(router *gin.Engine, passwordHandler handlers.PasswordHandler, tokens middlewares.TokenValidator) {
+	password := router.Group("/auth/password")
+
+	password.POST("/forgot", passwordHandler.ForgotPassword)
+	password.POST("/reset", passwordHandler.ResetPassword)
+	password.PUT("", middlewares.AuthMiddleware(tokens), passwordHandler.ChangePassword)
+}
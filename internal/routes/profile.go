@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+)
+
+func SetupProfileRoutes(router *gin.Engine, profileHandler handlers.ProfileHandler, tokens middlewares.TokenValidator) {
+	me := router.Group("/api/me", middlewares.AuthMiddleware(tokens))
+
+	me.GET("", profileHandler.GetMe)
+	me.PATCH("", profileHandler.UpdateMe)
+	me.DELETE("", profileHandler.DeleteMe)
+}
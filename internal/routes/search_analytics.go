@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+func SetupSearchAnalyticsRoutes(router *gin.Engine, analyticsHandler handlers.SearchAnalyticsHandler, tokens middlewares.TokenValidator) {
+	router.POST("/api/searches/:id/click", analyticsHandler.RecordClick)
+
+	analytics := router.Group("/api/search-analytics", middlewares.HasRole([]types.UserRole{types.RoleAdmin, types.RoleMerchant}, tokens))
+	analytics.GET("/", analyticsHandler.GetSearchReport)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+func SetupSettingsRoutes(router *gin.Engine, settingsHandler handlers.SettingsHandler, tokens middlewares.TokenValidator) {
+	settings := router.Group("/api/admin/settings", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens))
+
+	settings.GET("/security", settingsHandler.GetSecuritySettings)
+	settings.PUT("/security", settingsHandler.UpdateSecuritySettings)
+}
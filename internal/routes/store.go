@@ -9,4 +9,7 @@ func SetupStoreRoutes(router *gin.Engine, storeHandler handlers.StoreHandler) {
 	stores := router.Group("/api/stores")
 	stores.GET("/", storeHandler.ListStores)
 	stores.GET("/:id", storeHandler.GetStore)
+	stores.GET("/:id/faqs/suggest", storeHandler.SuggestFAQs)
+	stores.GET("/:id/faqs/top", storeHandler.TopFAQs)
+	stores.POST("/:id/faqs/:faqId/view", storeHandler.RecordView)
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kareemhamed001/faq/internal/handlers"
+	"github.com/kareemhamed001/faq/internal/middlewares"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+func SetupTrashRoutes(router *gin.Engine, trashHandler handlers.TrashHandler, tokens middlewares.TokenValidator) {
+	trash := router.Group("/api/admin/trash", middlewares.HasRole([]types.UserRole{types.RoleAdmin}, tokens))
+
+	trash.GET("/", trashHandler.ListTrash)
+	trash.POST("/:type/:id/restore", trashHandler.RestoreItem)
+}
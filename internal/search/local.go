@@ -0,0 +1,400 @@
+package search
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BM25 parameters. questionBoost counts question terms more than once, so a
+// match in the question outranks the same match in the answer.
+const (
+	bm25K1        = 1.2
+	bm25B         = 0.75
+	questionBoost = 2
+)
+
+type docKey struct {
+	FAQID    uint
+	Language string
+}
+
+type indexedDoc struct {
+	Document
+	length int
+}
+
+// LocalIndex is an in-process inverted index with BM25 scoring. It is kept in
+// memory and, when given a path, saved to disk so it survives restarts.
+type LocalIndex struct {
+	path string
+
+	saveMu sync.Mutex
+
+	mu          sync.RWMutex
+	docs        map[docKey]*indexedDoc
+	postings    map[string]map[docKey]int // term → document → term frequency
+	byFAQ       map[uint][]docKey
+	totalLength int
+	version     uint64 // bumped by every change
+	saved       uint64 // version last written to disk
+}
+
+// NewLocalIndex loads the index saved at path, if any. An empty path keeps
+// the index in memory only.
+func NewLocalIndex(path string) (*LocalIndex, error) {
+	idx := &LocalIndex{path: path}
+	idx.clear()
+
+	if path == "" {
+		return idx, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var docs []Document
+	if err := gob.NewDecoder(f).Decode(&docs); err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		idx.add(docs[i])
+	}
+
+	return idx, nil
+}
+
+// Len returns the number of indexed documents.
+func (x *LocalIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.docs)
+}
+
+// FAQIDs returns the ids of every indexed FAQ.
+func (x *LocalIndex) FAQIDs() []uint {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	ids := make([]uint, 0, len(x.byFAQ))
+	for id := range x.byFAQ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Documents returns the indexed documents of an FAQ.
+func (x *LocalIndex) Documents(faqID uint) []Document {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	docs := make([]Document, 0, len(x.byFAQ[faqID]))
+	for _, key := range x.byFAQ[faqID] {
+		docs = append(docs, x.docs[key].Document)
+	}
+	return docs
+}
+
+// Index replaces every document of an FAQ with docs.
+func (x *LocalIndex) Index(_ context.Context, faqID uint, docs []Document) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(faqID)
+	for _, doc := range docs {
+		doc.FAQID = faqID
+		x.add(doc)
+	}
+	x.version++
+	return nil
+}
+
+func (x *LocalIndex) Delete(_ context.Context, faqIDs ...uint) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for _, id := range faqIDs {
+		x.remove(id)
+	}
+	x.version++
+	return nil
+}
+
+func (x *LocalIndex) Reset(_ context.Context) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.clear()
+	x.version++
+	return nil
+}
+
+// Search scores every document containing a query term with BM25. An FAQ
+// scores as its best matching translation.
+func (x *LocalIndex) Search(_ context.Context, q Query) ([]Hit, error) {
+	matches := x.Match(q)
+
+	hits := make([]Hit, len(matches))
+	for i, m := range matches {
+		hits[i] = Hit{FAQID: m.FAQID, Score: m.Score}
+	}
+	return hits, nil
+}
+
+// Match is like Search but also reports, per FAQ, which translation matched
+// and how confident the match is.
+func (x *LocalIndex) Match(q Query) []Match {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	language := q.Language
+	if language == "" {
+		language = q.Filter.Language
+	}
+	terms := uniqueTerms(Analyze(language, q.Text))
+	if len(terms) == 0 || len(x.docs) == 0 {
+		return []Match{}
+	}
+
+	n := float64(len(x.docs))
+	avgLength := float64(x.totalLength) / n
+
+	type docScore struct {
+		score   float64
+		matched float64 // idf of the query terms found in the document
+	}
+	scores := make(map[docKey]*docScore)
+	var totalIDF float64
+	for _, term := range terms {
+		postings := x.postings[term]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		totalIDF += idf
+
+		for key, tf := range postings {
+			doc := x.docs[key]
+			if !q.Filter.Matches(&doc.Document) {
+				continue
+			}
+			freq := float64(tf)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(doc.length)/avgLength)
+
+			s := scores[key]
+			if s == nil {
+				s = &docScore{}
+				scores[key] = s
+			}
+			s.score += idf * freq * (bm25K1 + 1) / (freq + norm)
+			s.matched += idf
+		}
+	}
+
+	best := make(map[uint]Match)
+	for key, s := range scores {
+		if current, ok := best[key.FAQID]; ok && current.Score >= s.score {
+			continue
+		}
+		best[key.FAQID] = Match{
+			FAQID:      key.FAQID,
+			Language:   key.Language,
+			Score:      s.score,
+			Confidence: s.matched / totalIDF,
+		}
+	}
+
+	return topMatches(best, q.Limit)
+}
+
+// Save writes the index to its path if it changed since the last save. The
+// file is replaced atomically.
+func (x *LocalIndex) Save() error {
+	if x.path == "" {
+		return nil
+	}
+
+	x.saveMu.Lock()
+	defer x.saveMu.Unlock()
+
+	x.mu.RLock()
+	version := x.version
+	if version == x.saved {
+		x.mu.RUnlock()
+		return nil
+	}
+	docs := make([]Document, 0, len(x.docs))
+	for _, doc := range x.docs {
+		docs = append(docs, doc.Document)
+	}
+	x.mu.RUnlock()
+
+	if err := os.MkdirAll(filepath.Dir(x.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(x.path), filepath.Base(x.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(docs); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), x.path); err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	x.saved = version
+	x.mu.Unlock()
+	return nil
+}
+
+// StartSaving saves the index every interval until stop is closed, and once
+// more on the way out. The returned channel is closed after that last save.
+func (x *LocalIndex) StartSaving(interval time.Duration, stop <-chan struct{}, onError func(error)) <-chan struct{} {
+	done := make(chan struct{})
+	if x.path == "" || interval <= 0 {
+		close(done)
+		return done
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				if err := x.Save(); err != nil && onError != nil {
+					onError(err)
+				}
+				return
+			case <-ticker.C:
+				if err := x.Save(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+	return done
+}
+
+func (x *LocalIndex) clear() {
+	x.docs = make(map[docKey]*indexedDoc)
+	x.postings = make(map[string]map[docKey]int)
+	x.byFAQ = make(map[uint][]docKey)
+	x.totalLength = 0
+}
+
+// add indexes one document, replacing an earlier one in the same language.
+func (x *LocalIndex) add(doc Document) {
+	key := docKey{FAQID: doc.FAQID, Language: doc.Language}
+	if _, ok := x.docs[key]; ok {
+		x.removeDoc(key)
+	}
+
+	freqs := make(map[string]int)
+	length := 0
+	for _, term := range Analyze(doc.Language, doc.Question) {
+		freqs[term] += questionBoost
+		length += questionBoost
+	}
+	for _, term := range Analyze(doc.Language, doc.Answer) {
+		freqs[term]++
+		length++
+	}
+
+	x.docs[key] = &indexedDoc{Document: doc, length: length}
+	x.byFAQ[doc.FAQID] = append(x.byFAQ[doc.FAQID], key)
+	x.totalLength += length
+	for term, tf := range freqs {
+		if x.postings[term] == nil {
+			x.postings[term] = make(map[docKey]int)
+		}
+		x.postings[term][key] = tf
+	}
+}
+
+func (x *LocalIndex) remove(faqID uint) {
+	keys := append([]docKey(nil), x.byFAQ[faqID]...)
+	for _, key := range keys {
+		x.removeDoc(key)
+	}
+	delete(x.byFAQ, faqID)
+}
+
+func (x *LocalIndex) removeDoc(key docKey) {
+	doc, ok := x.docs[key]
+	if !ok {
+		return
+	}
+
+	for _, term := range Analyze(doc.Language, doc.Question+" "+doc.Answer) {
+		if postings, ok := x.postings[term]; ok {
+			delete(postings, key)
+			if len(postings) == 0 {
+				delete(x.postings, term)
+			}
+		}
+	}
+	x.totalLength -= doc.length
+	delete(x.docs, key)
+
+	keys := x.byFAQ[key.FAQID]
+	for i, k := range keys {
+		if k == key {
+			x.byFAQ[key.FAQID] = append(keys[:i], keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// topMatches orders matches best first, ties by id, and keeps at most limit.
+func topMatches(best map[uint]Match, limit int) []Match {
+	if limit <= 0 || limit > MaxHits {
+		limit = MaxHits
+	}
+
+	matches := make([]Match, 0, len(best))
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].FAQID < matches[j].FAQID
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
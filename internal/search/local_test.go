@@ -0,0 +1,234 @@
+package search
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func storeID(id uint) *uint { return &id }
+
+func newTestIndex(t *testing.T, docs ...Document) *LocalIndex {
+	t.Helper()
+
+	idx, err := NewLocalIndex("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byFAQ := make(map[uint][]Document)
+	var order []uint
+	for _, doc := range docs {
+		if _, ok := byFAQ[doc.FAQID]; !ok {
+			order = append(order, doc.FAQID)
+		}
+		byFAQ[doc.FAQID] = append(byFAQ[doc.FAQID], doc)
+	}
+	for _, id := range order {
+		if err := idx.Index(context.Background(), id, byFAQ[id]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return idx
+}
+
+func hitIDs(hits []Hit) []uint {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.FAQID
+	}
+	return ids
+}
+
+func search(t *testing.T, idx *LocalIndex, q Query) []uint {
+	t.Helper()
+	hits, err := idx.Search(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hitIDs(hits)
+}
+
+func TestLocalIndexRanking(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{FAQID: 1, Language: "en", Question: "How do I track my order?", Answer: "Use the link in your confirmation email."},
+		Document{FAQID: 2, Language: "en", Question: "Can I change my address?", Answer: "Yes, you can track and change it before the order ships."},
+		Document{FAQID: 3, Language: "en", Question: "Do you ship abroad?", Answer: "We ship to most countries."},
+		Document{FAQID: 4, Language: "en", Question: "What payment methods do you accept?", Answer: "Cards and bank transfers."},
+	)
+
+	tests := []struct {
+		name string
+		text string
+		want []uint
+	}{
+		{"question match outranks answer match", "track order", []uint{1, 2}},
+		{"stop words are ignored", "the a of", []uint{}},
+		{"case and punctuation are ignored", "PAYMENT-methods!", []uint{4}},
+		{"no match", "refund", []uint{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := search(t, idx, Query{Text: tt.text, Language: "en"}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("search %q = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalIndexRareTermsWeighMore(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{FAQID: 1, Language: "en", Question: "shipping costs", Answer: "shipping"},
+		Document{FAQID: 2, Language: "en", Question: "shipping customs", Answer: "shipping"},
+		Document{FAQID: 3, Language: "en", Question: "shipping times", Answer: "shipping"},
+	)
+
+	got := search(t, idx, Query{Text: "shipping customs", Language: "en"})
+	if len(got) != 3 || got[0] != 2 {
+		t.Errorf("search = %v, want FAQ 2 first of three", got)
+	}
+}
+
+func TestLocalIndexFilters(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{FAQID: 1, StoreID: storeID(10), CategoryID: 1, Language: "en", Question: "returns policy"},
+		Document{FAQID: 2, StoreID: storeID(20), CategoryID: 1, Language: "en", Question: "returns policy"},
+		Document{FAQID: 3, CategoryID: 2, Language: "en", Question: "returns policy"},
+		Document{FAQID: 3, CategoryID: 2, Language: "fr", Question: "politique de returns"},
+	)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []uint
+	}{
+		{"no filter", Filter{}, []uint{1, 2, 3}},
+		{"store only", Filter{StoreIDs: []uint{10}}, []uint{1}},
+		{"store and global", Filter{StoreIDs: []uint{10}, IncludeGlobal: true}, []uint{1, 3}},
+		{"category", Filter{CategoryIDs: []uint{2}}, []uint{3}},
+		{"language", Filter{Language: "fr"}, []uint{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := search(t, idx, Query{Text: "returns", Filter: tt.filter})
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("search = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalIndexLimit(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{FAQID: 1, Language: "en", Question: "gift cards"},
+		Document{FAQID: 2, Language: "en", Question: "gift wrapping"},
+		Document{FAQID: 3, Language: "en", Question: "gift receipts"},
+	)
+
+	if got := search(t, idx, Query{Text: "gift", Limit: 2}); len(got) != 2 {
+		t.Errorf("search with limit 2 returned %v", got)
+	}
+}
+
+func TestLocalIndexRemoval(t *testing.T) {
+	ctx := context.Background()
+	idx := newTestIndex(t,
+		Document{FAQID: 1, Language: "en", Question: "warranty claims", Answer: "Contact support."},
+		Document{FAQID: 1, Language: "de", Question: "Garantie", Answer: "Support kontaktieren."},
+		Document{FAQID: 2, Language: "en", Question: "warranty length", Answer: "Two years."},
+	)
+	if idx.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", idx.Len())
+	}
+
+	if err := idx.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if idx.Len() != 1 {
+		t.Errorf("Len after delete = %d, want 1", idx.Len())
+	}
+	if got := search(t, idx, Query{Text: "warranty"}); !reflect.DeepEqual(got, []uint{2}) {
+		t.Errorf("search after delete = %v, want [2]", got)
+	}
+	if got := search(t, idx, Query{Text: "garantie"}); len(got) != 0 {
+		t.Errorf("deleted translation still found: %v", got)
+	}
+	for _, term := range []string{"garantie", "claims", "support"} {
+		if _, ok := idx.postings[term]; ok {
+			t.Errorf("postings for %q left behind", term)
+		}
+	}
+
+	// Indexing an FAQ again replaces all of its translations.
+	if err := idx.Index(ctx, 2, []Document{{Language: "en", Question: "guarantee length"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := search(t, idx, Query{Text: "warranty"}); len(got) != 0 {
+		t.Errorf("replaced translation still found: %v", got)
+	}
+	if got := idx.Documents(2); len(got) != 1 || got[0].FAQID != 2 {
+		t.Errorf("Documents(2) = %+v, want the one new translation", got)
+	}
+
+	if err := idx.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if idx.Len() != 0 || len(idx.FAQIDs()) != 0 || idx.totalLength != 0 {
+		t.Errorf("index not empty after reset: %d docs, %v, length %d", idx.Len(), idx.FAQIDs(), idx.totalLength)
+	}
+}
+
+func TestLocalIndexMatchConfidence(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{FAQID: 1, Language: "en", Question: "cancel subscription"},
+		Document{FAQID: 2, Language: "en", Question: "cancel order"},
+		Document{FAQID: 2, Language: "fr", Question: "annuler commande"},
+	)
+
+	matches := idx.Match(Query{Text: "cancel subscription", Language: "en"})
+	if len(matches) != 2 {
+		t.Fatalf("Match returned %d matches, want 2", len(matches))
+	}
+	if matches[0].FAQID != 1 || matches[0].Confidence != 1 || matches[0].Language != "en" {
+		t.Errorf("best match = %+v, want FAQ 1 in en with confidence 1", matches[0])
+	}
+	if c := matches[1].Confidence; c <= 0 || c >= 1 {
+		t.Errorf("partial match confidence = %v, want between 0 and 1", c)
+	}
+
+	if got := idx.Match(Query{Text: "annuler", Filter: Filter{Language: "fr"}}); len(got) != 1 || got[0].Language != "fr" {
+		t.Errorf("Match in fr = %+v", got)
+	}
+}
+
+func TestLocalIndexSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faqs.idx")
+	idx, err := NewLocalIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := []Document{
+		{StoreID: storeID(3), CategoryID: 4, Language: "en", Question: "opening hours", Answer: "Nine to five."},
+	}
+	if err := idx.Index(context.Background(), 9, docs); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewLocalIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := search(t, loaded, Query{Text: "hours"}); !reflect.DeepEqual(got, []uint{9}) {
+		t.Errorf("search in loaded index = %v, want [9]", got)
+	}
+	want := docs[0]
+	want.FAQID = 9
+	if got := loaded.Documents(9); len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf("loaded documents = %+v, want %+v", got, want)
+	}
+}
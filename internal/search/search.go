@@ -0,0 +1,108 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxHits caps how many FAQs a single search returns.
+const MaxHits = 1000
+
+// Document is one translation of an FAQ as seen by a search index.
+type Document struct {
+	FAQID      uint
+	StoreID    *uint // nil for global FAQs
+	CategoryID uint
+	Language   string
+	Question   string
+	Answer     string
+}
+
+// Filter narrows a search. Zero values match everything.
+type Filter struct {
+	StoreIDs      []uint // FAQs of these stores
+	IncludeGlobal bool   // with StoreIDs, also global FAQs
+	CategoryIDs   []uint
+	Language      string
+}
+
+// Matches reports whether a document passes the filter.
+func (f Filter) Matches(doc *Document) bool {
+	if f.Language != "" && doc.Language != f.Language {
+		return false
+	}
+	if len(f.CategoryIDs) > 0 && !containsID(f.CategoryIDs, doc.CategoryID) {
+		return false
+	}
+	if len(f.StoreIDs) > 0 {
+		if doc.StoreID == nil {
+			return f.IncludeGlobal
+		}
+		return containsID(f.StoreIDs, *doc.StoreID)
+	}
+	return true
+}
+
+type Query struct {
+	Text     string
+	Language string // language of Text, for stop words; defaults to Filter.Language
+	Filter   Filter
+	Limit    int // at most MaxHits; zero means MaxHits
+}
+
+// Hit is a matching FAQ. Scores are only comparable within one search.
+type Hit struct {
+	FAQID uint    `json:"faq_id"`
+	Score float64 `json:"score"`
+}
+
+// Match is a Hit together with the translation that matched best. Confidence
+// is the share of the query's terms, weighted by how rare they are, that the
+// translation contains: 1 means every term was found.
+type Match struct {
+	FAQID      uint    `json:"faq_id"`
+	Language   string  `json:"language"`
+	Score      float64 `json:"score"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Tokenize lower-cases text and splits it into runs of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+	})
+}
+
+// Coverage is the share of the distinct terms that text contains, for indexes
+// that rank without term statistics and so cannot weigh terms by rarity.
+func Coverage(terms []string, text string) float64 {
+	found := make(map[string]bool)
+	for _, token := range Tokenize(text) {
+		found[token] = true
+	}
+
+	counted := make(map[string]bool, len(terms))
+	matched := 0
+	for _, term := range terms {
+		if counted[term] {
+			continue
+		}
+		counted[term] = true
+		if found[term] {
+			matched++
+		}
+	}
+	if len(counted) == 0 {
+		return 0
+	}
+	return float64(matched) / float64(len(counted))
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
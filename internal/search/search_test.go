@@ -0,0 +1,23 @@
+package search
+
+import "testing"
+
+func TestCoverage(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		text  string
+		want  float64
+	}{
+		{"all terms", []string{"track", "order"}, "How do I track my order?", 1},
+		{"half the terms", []string{"track", "refund"}, "Track your ORDER", 0.5},
+		{"repeated terms count once", []string{"track", "track", "refund"}, "track", 0.5},
+		{"no terms", nil, "anything", 0},
+		{"whole words only", []string{"order"}, "orders", 0},
+	}
+	for _, tt := range tests {
+		if got := Coverage(tt.terms, tt.text); got != tt.want {
+			t.Errorf("%s: Coverage = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package search
+
+import "strings"
+
+// stopWords are common words per language that carry no meaning for
+// matching. Languages without a list keep every word.
+var stopWords = map[string]map[string]bool{
+	"en": wordSet(`a about after all also am an and any are as at be been before being but by can could
+		did do does doing for from had has have having he her here hers him his how i if in into is it its
+		just me more most my no nor not of off on once only or other our ours out over own same she should
+		so some such than that the their theirs them then there these they this those through to too under
+		until up very was we were what when where which while who whom why will with would you your yours`),
+	"ar": wordSet(`في من على إلى عن مع هذا هذه ذلك تلك التي الذي الذين هو هي هم هن أنا نحن أنت أنتم
+		كان كانت يكون ما ماذا لماذا كيف متى أين هل لا لم لن قد ثم أو و ف ب ل إن أن كل بعض غير عند بين
+		حتى إذا لكن كما أي منذ خلال بعد قبل`),
+	"fr": wordSet(`a au aux avec ce ces cet cette comment dans de des du elle en est et il ils je la le les
+		leur lui ma mais me mes mon ne nos notre nous on ou où par pas pour qu que quel quelle qui sa se
+		ses son sont sur ta te tes ton tu un une vos votre vous y`),
+	"es": wordSet(`a al como con de del el ella ellos en es esta este esto hay la las le les lo los me mi
+		mis no nos o para pero por que qué se si sin su sus te tu tus un una uno unos unas y ya cómo cuál
+		cuando cuándo dónde`),
+	"de": wordSet(`aber als am an auch auf aus bei bin bis das dass dem den der des die doch du ein eine
+		einem einen einer er es für hat habe ich ihr im in ist ja kann mein mit nach nicht noch nur oder
+		sein sich sie sind so um und uns von vor war was wie wir wo zu zum zur`),
+}
+
+// Analyze tokenizes text and drops the stop words of its language. Regional
+// variants such as "en-GB" use the base language's list.
+func Analyze(language, text string) []string {
+	tokens := Tokenize(text)
+
+	words := stopWords[baseLanguage(language)]
+	if words == nil {
+		return tokens
+	}
+
+	kept := tokens[:0]
+	for _, token := range tokens {
+		if !words[token] {
+			kept = append(kept, token)
+		}
+	}
+	return kept
+}
+
+func baseLanguage(language string) string {
+	base, _, _ := strings.Cut(strings.ToLower(language), "-")
+	return base
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
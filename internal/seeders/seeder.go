@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/kareemhamed001/faq/internal/models"
 	"github.com/kareemhamed001/faq/internal/types"
@@ -35,12 +36,14 @@ func (s *Seeder) SeedAdminUser(name, email, password string) (*models.User, erro
 		return nil, fmt.Errorf("error hashing password: %w", err)
 	}
 
-	// Create admin user
+	// Create admin user; seeded accounts are trusted and need no email verification
+	verifiedAt := time.Now()
 	adminUser := models.User{
-		Name:     name,
-		Email:    email,
-		Password: string(hashedPassword),
-		Role:     types.RoleAdmin,
+		Name:            name,
+		Email:           email,
+		Password:        string(hashedPassword),
+		Role:            types.RoleAdmin,
+		EmailVerifiedAt: &verifiedAt,
 	}
 
 	if err := s.DB.Create(&adminUser).Error; err != nil {
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCannotModifySelf    = errors.New("admins cannot change their own role, status or account")
+	ErrInvalidRole         = errors.New("invalid role")
+	ErrAccountSuspended    = errors.New("account is suspended")
+	ErrUserAlreadyInStatus = errors.New("user is already in the requested status")
+)
+
+// UserFilter narrows the admin user listing.
+type UserFilter struct {
+	Role   types.UserRole
+	Email  string
+	Status string // "active", "suspended" or empty for both
+}
+
+type AdminUserService struct {
+	DB          *gorm.DB
+	authService *AuthService
+}
+
+func NewAdminUserService(DB *gorm.DB, authService *AuthService) *AdminUserService {
+	return &AdminUserService{DB: DB, authService: authService}
+}
+
+func (s *AdminUserService) ListUsers(ctx context.Context, filter UserFilter, page, pageSize int, sortDir string) ([]models.User, int64, error) {
+	query := s.DB.WithContext(ctx).Model(&models.User{})
+
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Email != "" {
+		query = query.Where("email ILIKE ?", "%"+filter.Email+"%")
+	}
+	switch filter.Status {
+	case "active":
+		query = query.Where("suspended_at IS NULL")
+	case "suspended":
+		query = query.Where("suspended_at IS NOT NULL")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	order := "users.id DESC"
+	if sortDir == "asc" {
+		order = "users.id ASC"
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := query.Preload("Store").
+		Order(order).
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (s *AdminUserService) GetUser(ctx context.Context, id uint) (*models.User, error) {
+	return s.loadUser(s.DB.WithContext(ctx), id)
+}
+
+// ChangeRole updates a user's role. Becoming a merchant creates the store if
+// needed; existing sessions are revoked so no token keeps the old role.
+func (s *AdminUserService) ChangeRole(ctx context.Context, adminID, id uint, role types.UserRole) (*models.User, error) {
+	if role != types.RoleAdmin && role != types.RoleMerchant && role != types.RoleCustomer {
+		return nil, ErrInvalidRole
+	}
+	if adminID == id {
+		return nil, ErrCannotModifySelf
+	}
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := s.loadUser(tx, id)
+		if err != nil {
+			return err
+		}
+		if user.Role == role {
+			return nil
+		}
+
+		if err := tx.Model(user).Update("role", role).Error; err != nil {
+			return err
+		}
+
+		if role == types.RoleMerchant && user.Store == nil {
+			store := models.Store{
+				Name:       user.Name + "'s Store",
+				MerchantID: user.ID,
+			}
+			if err := tx.Create(&store).Error; err != nil {
+				return err
+			}
+		}
+
+		return s.authService.revokeUserSessions(tx, user.ID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, id)
+}
+
+// Suspend blocks a user from logging in, revokes their sessions and API keys
+// and takes a merchant's store off the public endpoints.
+func (s *AdminUserService) Suspend(ctx context.Context, adminID, id uint) (*models.User, error) {
+	if adminID == id {
+		return nil, ErrCannotModifySelf
+	}
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := s.loadUser(tx, id)
+		if err != nil {
+			return err
+		}
+		if user.SuspendedAt != nil {
+			return ErrUserAlreadyInStatus
+		}
+
+		if err := tx.Model(user).Update("suspended_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return s.authService.revokeUserAccess(tx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, id)
+}
+
+// Reactivate lifts a suspension. Revoked sessions and API keys stay revoked.
+func (s *AdminUserService) Reactivate(ctx context.Context, id uint) (*models.User, error) {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := s.loadUser(tx, id)
+		if err != nil {
+			return err
+		}
+		if user.SuspendedAt == nil {
+			return ErrUserAlreadyInStatus
+		}
+
+		return tx.Model(user).Update("suspended_at", nil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, id)
+}
+
+// DeleteUser soft-deletes a user, together with a merchant's store and FAQs,
+// and revokes everything that authenticates as them.
+func (s *AdminUserService) DeleteUser(ctx context.Context, adminID, id uint) error {
+	if adminID == id {
+		return ErrCannotModifySelf
+	}
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := s.loadUser(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.authService.revokeUserAccess(tx, user.ID); err != nil {
+			return err
+		}
+
+		return softDeleteUser(tx, user.ID)
+	})
+}
+
+func (s *AdminUserService) loadUser(db *gorm.DB, id uint) (*models.User, error) {
+	var user models.User
+	if err := db.Preload("Store").First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrInvalidAPIKey      = errors.New("invalid, expired or revoked api key")
+	ErrInvalidAPIKeyScope = errors.New("api key scope must be read or read_write")
+	ErrInvalidExpiry      = errors.New("expiry must be in the future")
+)
+
+const (
+	apiKeyPrefix = "faq_"
+	// lastUsedResolution bounds how often last_used_at is written for a busy key.
+	lastUsedResolution = time.Minute
+)
+
+type APIKeyService struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyService(DB *gorm.DB) *APIKeyService {
+	return &APIKeyService{DB: DB}
+}
+
+// CreateAPIKey issues a key for the merchant's store. The raw key is only
+// returned here; afterwards just its prefix is visible.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userID uint, role types.UserRole, name string, scope types.APIKeyScope, expiresAt *time.Time) (*models.APIKey, string, error) {
+	if role != types.RoleMerchant {
+		return nil, "", ErrUnsupportedRole
+	}
+	if scope != types.APIKeyScopeRead && scope != types.APIKeyScopeReadWrite {
+		return nil, "", ErrInvalidAPIKeyScope
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidExpiry
+	}
+
+	storeID, err := s.getMerchantStoreID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := helpers.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	key := models.APIKey{
+		StoreID:   storeID,
+		UserID:    userID,
+		Name:      name,
+		Prefix:    rawKey[:len(apiKeyPrefix)+8],
+		KeyHash:   helpers.HashToken(rawKey),
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.DB.WithContext(ctx).Create(&key).Error; err != nil {
+		return nil, "", err
+	}
+
+	return &key, rawKey, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userID uint, role types.UserRole) ([]models.APIKey, error) {
+	if role != types.RoleMerchant {
+		return nil, ErrUnsupportedRole
+	}
+
+	storeID, err := s.getMerchantStoreID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []models.APIKey
+	err = s.DB.WithContext(ctx).
+		Where("store_id = ?", storeID).
+		Order("id DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userID uint, role types.UserRole, id uint) error {
+	if role != types.RoleMerchant {
+		return ErrUnsupportedRole
+	}
+
+	storeID, err := s.getMerchantStoreID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	result := s.DB.WithContext(ctx).
+		Model(&models.APIKey{}).
+		Where("id = ? AND store_id = ? AND revoked_at IS NULL", id, storeID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// ValidateAPIKey resolves a raw key to its record and records its use.
+func (s *APIKeyService) ValidateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := s.DB.WithContext(ctx).
+		Where("key_hash = ?", helpers.HashToken(rawKey)).
+		First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// The owner must still be an active merchant; keys do not outlive a role change or suspension.
+	var owner models.User
+	err = s.DB.WithContext(ctx).Select("id", "role", "suspended_at").First(&owner, key.UserID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner.Role != types.RoleMerchant || owner.SuspendedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.DB.WithContext(ctx).
+			Model(&models.APIKey{}).
+			Where("id = ?", key.ID).
+			Update("last_used_at", now).Error; err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return &key, nil
+}
+
+func (s *APIKeyService) getMerchantStoreID(ctx context.Context, merchantID uint) (uint, error) {
+	var store models.Store
+	err := s.DB.WithContext(ctx).Select("id").Where("merchant_id = ?", merchantID).First(&store).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrStoreNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return store.ID, nil
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/search"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+const maxAnswers = 10
+
+const (
+	// unansweredWindow is how long a logged question suppresses logging the
+	// same question for the store again.
+	unansweredWindow = time.Hour
+	// maxUnansweredPerWindow caps how many questions a store logs within
+	// unansweredWindow, so a flood of asks cannot grow the log without bound.
+	maxUnansweredPerWindow = 100
+)
+
+// AskAnswer is an FAQ that answers a customer's question, in the translation
+// that matched it.
+type AskAnswer struct {
+	FAQID      uint    `json:"faq_id"`
+	Language   string  `json:"language"`
+	Question   string  `json:"question"`
+	Answer     string  `json:"answer"`
+	Score      float64 `json:"score"`
+	Confidence float64 `json:"confidence"`
+}
+
+// AskService answers free-text questions from a store's FAQs and keeps the
+// questions it could not answer for the merchant.
+type AskService struct {
+	DB            *gorm.DB
+	indexer       *FAQIndexer
+	minConfidence float64
+}
+
+func NewAskService(DB *gorm.DB, indexer *FAQIndexer, minConfidence float64) *AskService {
+	return &AskService{DB: DB, indexer: indexer, minConfidence: minConfidence}
+}
+
+// Ask ranks the store's FAQs and the global FAQs against question in the
+// shared search index, and returns up to limit live matches whose confidence
+// reaches the configured minimum. When none does, the question is logged as
+// unanswered together with the closest live match.
+func (s *AskService) Ask(ctx context.Context, storeID uint, question, language string, limit int) ([]AskAnswer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, ErrSearchQueryRequired
+	}
+	if limit <= 0 || limit > maxAnswers {
+		limit = 3
+	}
+
+	if _, err := findActiveStore(s.DB.WithContext(ctx), storeID); err != nil {
+		return nil, err
+	}
+
+	matches, err := s.indexer.Match(ctx, search.Query{
+		Text:     question,
+		Language: language,
+		Filter:   search.Filter{StoreIDs: []uint{storeID}, IncludeGlobal: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The index also holds drafts, scheduled and expired FAQs.
+	ids := make([]uint, len(matches))
+	for i, m := range matches {
+		ids[i] = m.FAQID
+	}
+	var faqs []models.FAQ
+	if len(ids) > 0 {
+		err := s.DB.WithContext(ctx).
+			Where("id IN ?", ids).
+			Where("store_id = ? OR is_global = ?", storeID, true).
+			Scopes(liveFAQs(time.Now())).
+			Preload("Translations").
+			Find(&faqs).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uint]*models.FAQ, len(faqs))
+	for i := range faqs {
+		byID[faqs[i].ID] = &faqs[i]
+	}
+
+	answers := []AskAnswer{}
+	var closest *search.Match
+	for i, m := range matches {
+		faq := byID[m.FAQID]
+		if faq == nil {
+			continue
+		}
+		if closest == nil {
+			closest = &matches[i]
+		}
+		if len(answers) == limit {
+			break
+		}
+		if m.Confidence < s.minConfidence {
+			continue
+		}
+		for _, t := range faq.Translations {
+			if t.Language == m.Language {
+				answers = append(answers, AskAnswer{
+					FAQID:      m.FAQID,
+					Language:   t.Language,
+					Question:   t.Question,
+					Answer:     t.Answer,
+					Score:      m.Score,
+					Confidence: m.Confidence,
+				})
+				break
+			}
+		}
+	}
+
+	if len(answers) == 0 {
+		unanswered := models.UnansweredQuestion{
+			StoreID:  storeID,
+			Language: helpers.NormalizeLanguage(language),
+			Question: question,
+		}
+		if closest != nil {
+			unanswered.BestFAQID = &closest.FAQID
+			unanswered.BestConfidence = &closest.Confidence
+		}
+		if err := s.logUnanswered(ctx, &unanswered); err != nil {
+			return nil, err
+		}
+	}
+
+	return answers, nil
+}
+
+// logUnanswered saves an unanswered question unless the store logged the same
+// question, ignoring case, within unansweredWindow or already logged
+// maxUnansweredPerWindow questions in it.
+func (s *AskService) logUnanswered(ctx context.Context, unanswered *models.UnansweredQuestion) error {
+	var recent struct {
+		Total int64
+		Same  int64
+	}
+	err := s.DB.WithContext(ctx).
+		Model(&models.UnansweredQuestion{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE lower(question) = lower(?)) AS same", unanswered.Question).
+		Where("store_id = ? AND created_at > ?", unanswered.StoreID, time.Now().Add(-unansweredWindow)).
+		Scan(&recent).Error
+	if err != nil {
+		return err
+	}
+	if recent.Same > 0 || recent.Total >= maxUnansweredPerWindow {
+		return nil
+	}
+	return s.DB.WithContext(ctx).Create(unanswered).Error
+}
+
+// ListUnansweredQuestions pages through logged questions, newest first.
+// Merchants see the questions asked in their own stores.
+func (s *AskService) ListUnansweredQuestions(ctx context.Context, role types.UserRole, userId uint, page, pageSize int) ([]models.UnansweredQuestion, int64, error) {
+	query := s.DB.WithContext(ctx).Model(&models.UnansweredQuestion{})
+
+	switch role {
+	case types.RoleAdmin:
+	case types.RoleMerchant:
+		query = query.Where("store_id IN (SELECT id FROM stores WHERE merchant_id = ?)", userId)
+	default:
+		return nil, 0, ErrUnsupportedRole
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	questions := []models.UnansweredQuestion{}
+	err := query.Order("created_at DESC, id DESC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&questions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return questions, total, nil
+}
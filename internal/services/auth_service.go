@@ -1,22 +1,82 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/kareemhamed001/faq/internal/helpers"
 	"github.com/kareemhamed001/faq/internal/models"
 	"github.com/kareemhamed001/faq/internal/types"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected; session revoked")
+	ErrInvalidAccessToken  = errors.New("invalid access token")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+)
+
+const (
+	accessTokenType        = "access"
+	mfaChallengeTokenType  = "mfa_challenge"
+	mfaEnrollmentTokenType = "mfa_enrollment"
+)
+
+// dummyPasswordHash is compared against when the email is unknown so that
+// failed logins take the same time whether or not the account exists.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+// TokenPair is the credential set handed to a client after login or refresh.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+// LoginResult is the outcome of a password login. Tokens is nil when a second
+// step is needed; MFAToken then authorizes either the TOTP check or, when
+// MFAEnrollmentRequired is set, the mandatory TOTP enrollment.
+type LoginResult struct {
+	User                  *models.User
+	Tokens                *TokenPair
+	MFARequired           bool
+	MFAEnrollmentRequired bool
+	MFAToken              string
+	MFATokenExpiresIn     int64
+}
+
+// AuthOptions configures token lifetimes for AuthService.
+type AuthOptions struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	MFAChallengeTTL time.Duration
+}
+
 type AuthService struct {
-	DB            *gorm.DB
-	JWTPrivateKey string
+	DB              *gorm.DB
+	Keys            *helpers.KeySet
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	MFAChallengeTTL time.Duration
+	settings        *SettingsService
+	throttle        *LoginThrottleService
 }
 
-func NewAuthService(DB *gorm.DB, JWTPrivateKey string) *AuthService {
-	return &AuthService{DB: DB, JWTPrivateKey: JWTPrivateKey}
+func NewAuthService(DB *gorm.DB, keys *helpers.KeySet, settings *SettingsService, throttle *LoginThrottleService, options AuthOptions) *AuthService {
+	return &AuthService{
+		DB:              DB,
+		Keys:            keys,
+		AccessTokenTTL:  options.AccessTokenTTL,
+		RefreshTokenTTL: options.RefreshTokenTTL,
+		MFAChallengeTTL: options.MFAChallengeTTL,
+		settings:        settings,
+		throttle:        throttle,
+	}
 }
 
 func (s *AuthService) Register(name, email, password string, role types.UserRole) (*models.User, error) {
@@ -70,26 +130,312 @@ func (s *AuthService) Register(name, email, password string, role types.UserRole
 	return &user, nil
 }
 
-func (s *AuthService) Login(email, password string) (*models.User, string, error) {
+// Login checks the password and either opens a session or asks for a second
+// factor. Unknown emails and wrong passwords yield the same ErrInvalidCredentials,
+// and repeated failures lock the account and client IP out temporarily.
+func (s *AuthService) Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error) {
+	if err := s.throttle.Check(ctx, email, clientIP); err != nil {
+		return nil, err
+	}
+
 	var user models.User
-	err := s.DB.Preload("Store").Where("email=?", email).First(&user).Error
+	err := s.DB.WithContext(ctx).Preload("Store").Where("email=?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, s.loginFailed(ctx, email, clientIP)
+	}
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, "", err
+		return nil, s.loginFailed(ctx, email, clientIP)
+	}
+
+	if user.SuspendedAt != nil {
+		return nil, ErrAccountSuspended
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return s.mfaLoginResult(&user, mfaChallengeTokenType)
+	}
+
+	if user.Role == types.RoleAdmin {
+		requireMFA, err := s.settings.GetBool(ctx, SettingRequireAdminMFA, false)
+		if err != nil {
+			return nil, err
+		}
+		if requireMFA {
+			return s.mfaLoginResult(&user, mfaEnrollmentTokenType)
+		}
+	}
+
+	if err := s.throttle.RegisterSuccess(ctx, email); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(s.DB.WithContext(ctx), &user)
+	if err != nil {
+		return nil, err
 	}
 
-	signedToken, err := helpers.GenerateToken(map[string]interface{}{
+	return &LoginResult{User: &user, Tokens: tokens}, nil
+}
+
+func (s *AuthService) loginFailed(ctx context.Context, email, clientIP string) error {
+	if err := s.throttle.RegisterFailure(ctx, email, clientIP); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+func (s *AuthService) mfaLoginResult(user *models.User, tokenType string) (*LoginResult, error) {
+	mfaToken, err := helpers.GenerateToken(map[string]interface{}{
+		"sub": user.ID,
+		"typ": tokenType,
+		"exp": time.Now().Add(s.MFAChallengeTTL).Unix(),
+	}, s.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{
+		User:                  user,
+		MFARequired:           tokenType == mfaChallengeTokenType,
+		MFAEnrollmentRequired: tokenType == mfaEnrollmentTokenType,
+		MFAToken:              mfaToken,
+		MFATokenExpiresIn:     int64(s.MFAChallengeTTL.Seconds()),
+	}, nil
+}
+
+// parseMFAToken validates a login MFA token of the given type and returns the user id it was issued for.
+func (s *AuthService) parseMFAToken(token, tokenType string) (uint, error) {
+	claims, err := helpers.ValidateToken(token, s.Keys)
+	if err != nil {
+		return 0, ErrInvalidMFAToken
+	}
+	if typ, _ := claims["typ"].(string); typ != tokenType {
+		return 0, ErrInvalidMFAToken
+	}
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return 0, ErrInvalidMFAToken
+	}
+	return uint(userID), nil
+}
+
+// startSession opens a new session (refresh token family) for a fully authenticated user.
+func (s *AuthService) startSession(db *gorm.DB, user *models.User) (*TokenPair, error) {
+	familyID, err := helpers.GenerateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens *TokenPair
+	err = db.Transaction(func(tx *gorm.DB) error {
+		pair, _, err := s.issueTokens(tx, user, familyID)
+		if err != nil {
+			return err
+		}
+		tokens = pair
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// pair is issued in the same session. Presenting a token that was already
+// rotated or revoked is treated as theft and revokes the whole session.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.User, *TokenPair, error) {
+	var (
+		user   models.User
+		tokens *TokenPair
+		reused bool
+	)
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", helpers.HashToken(refreshToken)).
+			First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			reused = true
+			return s.revokeFamily(tx, current.FamilyID)
+		}
+
+		if time.Now().After(current.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := tx.Preload("Store").First(&user, current.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if user.SuspendedAt != nil {
+			return ErrAccountSuspended
+		}
+
+		pair, next, err := s.issueTokens(tx, &user, current.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&current).Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"replaced_by_id": next.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		tokens = pair
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+	if reused {
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	return &user, tokens, nil
+}
+
+// Logout revokes the session the given refresh token belongs to.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	var current models.RefreshToken
+	err := s.DB.WithContext(ctx).
+		Where("token_hash = ?", helpers.HashToken(refreshToken)).
+		First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.revokeFamily(s.DB.WithContext(ctx), current.FamilyID)
+}
+
+// LogoutAll revokes every session of the given user.
+func (s *AuthService) LogoutAll(ctx context.Context, userID uint) error {
+	return s.revokeUserSessions(s.DB.WithContext(ctx), userID, "")
+}
+
+// ValidateAccessToken verifies the token signature and type and makes sure
+// its session has not been revoked.
+func (s *AuthService) ValidateAccessToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	claims, err := helpers.ValidateToken(token, s.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ, _ := claims["typ"].(string); typ != accessTokenType {
+		return nil, ErrInvalidAccessToken
+	}
+
+	sessionID, _ := claims["sid"].(string)
+	if sessionID == "" {
+		return nil, ErrInvalidAccessToken
+	}
+
+	active, err := s.isSessionActive(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrSessionRevoked
+	}
+
+	return claims, nil
+}
+
+func (s *AuthService) isSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	var count int64
+	err := s.DB.WithContext(ctx).
+		Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// issueTokens creates a new refresh token in the given session and signs a matching access token.
+func (s *AuthService) issueTokens(db *gorm.DB, user *models.User, familyID string) (*TokenPair, *models.RefreshToken, error) {
+	rawRefresh, err := helpers.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: helpers.HashToken(rawRefresh),
+		ExpiresAt: time.Now().Add(s.RefreshTokenTTL),
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return nil, nil, err
+	}
+
+	accessToken, err := helpers.GenerateToken(map[string]interface{}{
 		"sub":     user.ID,
 		"user_id": user.ID,
 		"role":    user.Role,
-	}, s.JWTPrivateKey)
-
+		"sid":     familyID,
+		"typ":     accessTokenType,
+		"exp":     time.Now().Add(s.AccessTokenTTL).Unix(),
+	}, s.Keys)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	return &user, signedToken, nil
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawRefresh,
+		ExpiresIn:    int64(s.AccessTokenTTL.Seconds()),
+	}, &record, nil
+}
+
+func (s *AuthService) revokeFamily(db *gorm.DB, familyID string) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// revokeUserSessions revokes all sessions of a user except keepFamilyID (if non-empty).
+func (s *AuthService) revokeUserSessions(db *gorm.DB, userID uint, keepFamilyID string) error {
+	query := db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if keepFamilyID != "" {
+		query = query.Where("family_id <> ?", keepFamilyID)
+	}
+	return query.Update("revoked_at", time.Now()).Error
+}
+
+// revokeUserAccess revokes every session and API key of a user.
+func (s *AuthService) revokeUserAccess(db *gorm.DB, userID uint) error {
+	if err := s.revokeUserSessions(db, userID, ""); err != nil {
+		return err
+	}
+
+	return db.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
 }
@@ -1,10 +1,22 @@
 package services
 
 import (
+	"errors"
+	"sort"
+
+	dtos "github.com/kareemhamed001/faq/internal/DTOs"
 	"github.com/kareemhamed001/faq/internal/models"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCategoryInUse               = errors.New("category still has faqs")
+	ErrCategoryHasChildren         = errors.New("category still has subcategories")
+	ErrParentCategoryNotFound      = errors.New("parent category not found")
+	ErrCategoryCycle               = errors.New("a category cannot be moved under itself or its subcategories")
+	ErrInvalidCategoryTranslations = errors.New("category translations need a language and a name, at most one per language")
+)
+
 type FAQCategoryService struct {
 	DB *gorm.DB
 }
@@ -13,46 +25,170 @@ func NewFAQCategoryService(DB *gorm.DB) *FAQCategoryService {
 	return &FAQCategoryService{DB: DB}
 }
 
-func (s *FAQCategoryService) GetAllCategories() ([]models.Category, error) {
+// GetAllCategories returns every category with its translations, each named
+// in language where it has a translation for it.
+func (s *FAQCategoryService) GetAllCategories(language string) ([]models.Category, error) {
 	var faqCategories []models.Category
-	err := s.DB.Find(&faqCategories).Error
+	err := s.DB.Preload("Translations").Find(&faqCategories).Error
 	if err != nil {
 		return nil, err
 	}
+	for i := range faqCategories {
+		localizeCategory(&faqCategories[i], language)
+	}
 	return faqCategories, nil
 }
 
-func (s *FAQCategoryService) GetCategoryByID(id uint) (*models.Category, error) {
+// GetCategoryTree returns the top-level categories with their subcategories
+// nested under Children, each level ordered by name in language.
+func (s *FAQCategoryService) GetCategoryTree(language string) ([]models.Category, error) {
+	var categories []models.Category
+	if err := s.DB.Preload("Translations").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		localizeCategory(&categories[i], language)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].Name != categories[j].Name {
+			return categories[i].Name < categories[j].Name
+		}
+		return categories[i].ID < categories[j].ID
+	})
+
+	byParent := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		byParent[*category.ParentID] = append(byParent[*category.ParentID], category)
+	}
+
+	var attach func(nodes []models.Category) []models.Category
+	attach = func(nodes []models.Category) []models.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(byParent[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	tree := attach(roots)
+	if tree == nil {
+		tree = []models.Category{}
+	}
+	return tree, nil
+}
+
+func (s *FAQCategoryService) GetCategoryByID(id uint, language string) (*models.Category, error) {
 	var category models.Category
-	err := s.DB.First(&category, id).Error
+	err := s.DB.Preload("Translations").First(&category, id).Error
 	if err != nil {
 		return nil, err
 	}
+	localizeCategory(&category, language)
 	return &category, nil
 }
-func (s *FAQCategoryService) CreateCategory(name string) (*models.Category, error) {
+
+// CreateCategory creates a category named name, which is shown in languages
+// it has no translation for.
+func (s *FAQCategoryService) CreateCategory(name string, parentID *uint, translations []dtos.CategoryTranslationDTO) (*models.Category, error) {
+	if err := validateCategoryTranslations(translations); err != nil {
+		return nil, err
+	}
+
 	category := models.Category{
-		Name: name,
+		Name:     name,
+		ParentID: parentID,
 	}
 
-	err := s.DB.Create(&category).Error
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if parentID != nil {
+			if err := assertParentCategoryExists(tx, *parentID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+		return syncCategoryTranslations(tx, &category, translations)
+	})
 	if err != nil {
 		return nil, err
-
 	}
 	return &category, nil
 }
 
-func (s *FAQCategoryService) UpdateCategory(id uint, name string) (*models.Category, error) {
+// UpdateCategory renames a category. When translations is not nil it replaces
+// the category's translations; languages left out of it are removed.
+func (s *FAQCategoryService) UpdateCategory(id uint, name string, translations []dtos.CategoryTranslationDTO) (*models.Category, error) {
+	if err := validateCategoryTranslations(translations); err != nil {
+		return nil, err
+	}
+
 	var category models.Category
-	err := s.DB.First(&category, id).Error
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Translations").First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+			return err
+		}
+
+		category.Name = name
+		if err := tx.Model(&category).Update("name", name).Error; err != nil {
+			return err
+		}
+
+		if translations == nil {
+			return nil
+		}
+		return syncCategoryTranslations(tx, &category, translations)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	category.Name = name
+	return &category, nil
+}
+
+// MoveCategory moves a category, with its whole subtree, under another
+// parent, or to the top level when parentID is nil. A category cannot be
+// moved under itself or one of its descendants.
+func (s *FAQCategoryService) MoveCategory(id uint, parentID *uint) (*models.Category, error) {
+	var category models.Category
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Serializes moves, so two concurrent moves cannot close a cycle.
+		if err := tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+			return err
+		}
+
+		if parentID != nil {
+			if err := assertParentCategoryExists(tx, *parentID); err != nil {
+				return err
+			}
+			subtree, err := categorySubtreeIDs(tx, id)
+			if err != nil {
+				return err
+			}
+			for _, subtreeID := range subtree {
+				if subtreeID == *parentID {
+					return ErrCategoryCycle
+				}
+			}
+		}
 
-	err = s.DB.Save(&category).Error
+		category.ParentID = parentID
+		return tx.Model(&category).Update("parent_id", parentID).Error
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -60,19 +196,140 @@ func (s *FAQCategoryService) UpdateCategory(id uint, name string) (*models.Categ
 	return &category, nil
 }
 
+// DeleteCategory moves a category to the trash. Categories that still have
+// FAQs or subcategories cannot be deleted.
 func (s *FAQCategoryService) DeleteCategory(id uint) error {
-	err := s.DB.Delete(&models.Category{}, id).Error
+	var faqs int64
+	err := s.DB.Model(&models.FAQ{}).Where("category_id = ?", id).Count(&faqs).Error
 	if err != nil {
 		return err
 	}
+	if faqs > 0 {
+		return ErrCategoryInUse
+	}
+
+	var children int64
+	if err := s.DB.Model(&models.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	result := s.DB.Delete(&models.Category{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCategoryNotFound
+	}
 	return nil
 }
 
-func (s *FAQCategoryService) SearchCategories(search string) ([]models.Category, error) {
+// SearchCategories matches search against the names of categories in every
+// language.
+func (s *FAQCategoryService) SearchCategories(search, language string) ([]models.Category, error) {
 	var categories []models.Category
-	err := s.DB.Where("name ILIKE ?", "%"+search+"%").Find(&categories).Error
+	pattern := "%" + search + "%"
+	err := s.DB.Preload("Translations").
+		Where("name ILIKE ? OR id IN (SELECT category_id FROM category_translations WHERE name ILIKE ?)", pattern, pattern).
+		Find(&categories).Error
 	if err != nil {
 		return nil, err
 	}
+	for i := range categories {
+		localizeCategory(&categories[i], language)
+	}
 	return categories, nil
 }
+
+// categorySubtreeIDs returns the id of a live category and of all its live
+// descendants, or nothing when the category does not exist.
+func categorySubtreeIDs(db *gorm.DB, id uint) ([]uint, error) {
+	var ids []uint
+	err := db.Raw(`WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT categories.id FROM categories
+			JOIN subtree ON categories.parent_id = subtree.id
+			WHERE categories.deleted_at IS NULL
+		)
+		SELECT id FROM subtree`, id).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func assertParentCategoryExists(db *gorm.DB, id uint) error {
+	var count int64
+	if err := db.Model(&models.Category{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrParentCategoryNotFound
+	}
+	return nil
+}
+
+func validateCategoryTranslations(translations []dtos.CategoryTranslationDTO) error {
+	seen := make(map[string]bool, len(translations))
+	for _, t := range translations {
+		if t.Language == "" || t.Name == "" || seen[t.Language] {
+			return ErrInvalidCategoryTranslations
+		}
+		seen[t.Language] = true
+	}
+	return nil
+}
+
+// syncCategoryTranslations replaces the translations of a category.
+func syncCategoryTranslations(tx *gorm.DB, category *models.Category, translations []dtos.CategoryTranslationDTO) error {
+	if err := tx.Where("category_id = ?", category.ID).Delete(&models.CategoryTranslation{}).Error; err != nil {
+		return err
+	}
+
+	category.Translations = make([]models.CategoryTranslation, len(translations))
+	if len(translations) == 0 {
+		return nil
+	}
+	for i, t := range translations {
+		category.Translations[i] = models.CategoryTranslation{
+			CategoryID: category.ID,
+			Language:   t.Language,
+			Name:       t.Name,
+		}
+	}
+	return tx.Create(&category.Translations).Error
+}
+
+// localizeCategory names a category, and its loaded subcategories, in the
+// requested language, falling back to English and then to the category's own
+// name, as filterTranslations does for FAQs. Its translations must be loaded.
+func localizeCategory(category *models.Category, language string) {
+	category.Name = categoryName(category, language)
+	for i := range category.Children {
+		localizeCategory(&category.Children[i], language)
+	}
+}
+
+// localizeFAQCategory names the category of an FAQ in language and drops its
+// translations, which the FAQ endpoints do not show.
+func localizeFAQCategory(faq *models.FAQ, language string) {
+	localizeCategory(&faq.Category, language)
+	faq.Category.Translations = nil
+}
+
+func categoryName(category *models.Category, language string) string {
+	for _, t := range category.Translations {
+		if t.Language == language {
+			return t.Name
+		}
+	}
+	for _, t := range category.Translations {
+		if t.Language == "en" {
+			return t.Name
+		}
+	}
+	return category.Name
+}
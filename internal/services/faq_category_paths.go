@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"gorm.io/gorm"
+)
+
+// categoryPathSeparator joins a category's name to its ancestors' in import
+// and export files, e.g. "Shipping > International".
+const categoryPathSeparator = " > "
+
+// categoryPaths names categories by their path for imports and exports, since
+// nested categories make duplicate names normal.
+type categoryPaths struct {
+	paths  map[uint]string
+	byPath map[string][]uint // lower-cased path
+	byName map[string][]uint // lower-cased name
+}
+
+func loadCategoryPaths(db *gorm.DB) (*categoryPaths, error) {
+	var categories []models.Category
+	if err := db.Select("id", "name", "parent_id").Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return newCategoryPaths(categories), nil
+}
+
+func newCategoryPaths(categories []models.Category) *categoryPaths {
+	byID := make(map[uint]*models.Category, len(categories))
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
+	}
+
+	c := &categoryPaths{
+		paths:  make(map[uint]string, len(categories)),
+		byPath: make(map[string][]uint, len(categories)),
+		byName: make(map[string][]uint, len(categories)),
+	}
+	for _, category := range categories {
+		// Walk up at most len(categories) parents, in case of a cycle. A
+		// parent that is gone ends the path.
+		names := []string{category.Name}
+		for parent, depth := category.ParentID, 0; parent != nil && depth < len(categories); depth++ {
+			p, ok := byID[*parent]
+			if !ok {
+				break
+			}
+			names = append([]string{p.Name}, names...)
+			parent = p.ParentID
+		}
+
+		path := strings.Join(names, categoryPathSeparator)
+		c.paths[category.ID] = path
+		key := categoryPathKey(path)
+		c.byPath[key] = append(c.byPath[key], category.ID)
+		name := categoryPathKey(category.Name)
+		c.byName[name] = append(c.byName[name], category.ID)
+	}
+	return c
+}
+
+// Path returns the path of a category, or "" if it does not exist.
+func (c *categoryPaths) Path(id uint) string {
+	return c.paths[id]
+}
+
+// Resolve finds the category a file refers to, either by its path or by a bare
+// name that a single category has. Matching ignores case and the spacing
+// around separators.
+func (c *categoryPaths) Resolve(ref string) (uint, error) {
+	key := categoryPathKey(ref)
+	ids := c.byPath[key]
+	if len(ids) == 0 && !strings.Contains(key, ">") {
+		ids = c.byName[key]
+	}
+
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("category %q not found", ref)
+	case 1:
+		return ids[0], nil
+	default:
+		paths := make([]string, len(ids))
+		for i, id := range ids {
+			paths[i] = fmt.Sprintf("%q", c.paths[id])
+		}
+		return 0, fmt.Errorf("category %q is ambiguous, use one of the paths %s", ref, strings.Join(paths, ", "))
+	}
+}
+
+func categoryPathKey(path string) string {
+	names := strings.Split(path, ">")
+	for i, name := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	return strings.Join(names, categoryPathSeparator)
+}
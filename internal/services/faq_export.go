@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	dtos "github.com/kareemhamed001/faq/internal/DTOs"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+const (
+	exportBatchSize   = 200
+	exportedKeyPrefix = "faq-"
+)
+
+// exportColumns are the CSV and XLSX columns, one row per FAQ and language.
+var exportColumns = []string{"id", "external_key", "store_id", "category", "status", "language", "question", "answer", "publish_at", "expire_at"}
+
+var exportContentTypes = map[types.FAQFileFormat]string{
+	types.FAQFileCSV:  "text/csv; charset=utf-8",
+	types.FAQFileJSON: "application/json; charset=utf-8",
+	types.FAQFileYAML: "application/yaml; charset=utf-8",
+	types.FAQFileXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ExportContentType returns the MIME type of an export format.
+func ExportContentType(format types.FAQFileFormat) (string, error) {
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return "", ErrUnsupportedFileFormat
+	}
+	return contentType, nil
+}
+
+// ExportFAQs writes every FAQ the caller can see, with all of its
+// translations, to w. FAQs are read in batches so large catalogues stream
+// without being held in memory. Files can be imported again as they are: FAQs
+// without an external_key are exported as "faq-<id>".
+func (s *FAQService) ExportFAQs(ctx context.Context, w io.Writer, format types.FAQFileFormat, filter FAQFilter, role types.UserRole, userId uint) error {
+	if _, err := ExportContentType(format); err != nil {
+		return err
+	}
+
+	query, err := s.filterFAQs(ctx, filter, role, userId)
+	if err != nil {
+		return err
+	}
+	categories, err := loadCategoryPaths(s.DB.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	query = query.
+		Preload("Translations", func(db *gorm.DB) *gorm.DB { return db.Order("language") }).
+		Order("faqs.id").
+		Limit(exportBatchSize).
+		Session(&gorm.Session{})
+
+	out, err := newFAQExportWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	var lastID uint
+	for {
+		var faqs []models.FAQ
+		if err := query.Where("faqs.id > ?", lastID).Find(&faqs).Error; err != nil {
+			return err
+		}
+
+		for _, faq := range faqs {
+			if err := out.Write(exportRecord(&faq, categories)); err != nil {
+				return err
+			}
+		}
+
+		if len(faqs) < exportBatchSize {
+			break
+		}
+		lastID = faqs[len(faqs)-1].ID
+	}
+
+	return out.Close()
+}
+
+func exportRecord(faq *models.FAQ, categories *categoryPaths) dtos.FAQRecord {
+	record := dtos.FAQRecord{
+		ID:           faq.ID,
+		ExternalKey:  exportedKeyPrefix + strconv.FormatUint(uint64(faq.ID), 10),
+		StoreID:      faq.StoreID,
+		Category:     categories.Path(faq.CategoryID),
+		Status:       string(faq.Status),
+		PublishAt:    faq.PublishAt,
+		ExpireAt:     faq.ExpireAt,
+		Translations: make([]dtos.TranslationDTO, 0, len(faq.Translations)),
+	}
+	if faq.ExternalKey != nil {
+		record.ExternalKey = *faq.ExternalKey
+	}
+	for _, t := range faq.Translations {
+		record.Translations = append(record.Translations, dtos.TranslationDTO{
+			Language: t.Language,
+			Question: t.Question,
+			Answer:   t.Answer,
+		})
+	}
+	return record
+}
+
+type faqExportWriter interface {
+	Write(record dtos.FAQRecord) error
+	Close() error
+}
+
+func newFAQExportWriter(w io.Writer, format types.FAQFileFormat) (faqExportWriter, error) {
+	switch format {
+	case types.FAQFileCSV:
+		out := &tableExportWriter{csv: csv.NewWriter(w)}
+		return out, out.writeRow(exportColumns)
+	case types.FAQFileXLSX:
+		sheet, err := helpers.NewXLSXWriter(w, "FAQs")
+		if err != nil {
+			return nil, err
+		}
+		out := &tableExportWriter{xlsx: sheet}
+		return out, out.writeRow(exportColumns)
+	case types.FAQFileJSON:
+		return &jsonExportWriter{w: w}, nil
+	case types.FAQFileYAML:
+		return &yamlExportWriter{w: w}, nil
+	default:
+		return nil, ErrUnsupportedFileFormat
+	}
+}
+
+// tableExportWriter writes one CSV or XLSX row per translation.
+type tableExportWriter struct {
+	csv  *csv.Writer
+	xlsx *helpers.XLSXWriter
+}
+
+func (t *tableExportWriter) Write(record dtos.FAQRecord) error {
+	storeID := ""
+	if record.StoreID != nil {
+		storeID = strconv.FormatUint(uint64(*record.StoreID), 10)
+	}
+
+	translations := record.Translations
+	if len(translations) == 0 {
+		translations = []dtos.TranslationDTO{{}}
+	}
+	for _, tr := range translations {
+		err := t.writeRow([]string{
+			strconv.FormatUint(uint64(record.ID), 10),
+			record.ExternalKey,
+			storeID,
+			record.Category,
+			record.Status,
+			tr.Language,
+			tr.Question,
+			tr.Answer,
+			formatExportTime(record.PublishAt),
+			formatExportTime(record.ExpireAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.csv != nil {
+		t.csv.Flush()
+		return t.csv.Error()
+	}
+	return nil
+}
+
+func (t *tableExportWriter) writeRow(row []string) error {
+	for i, cell := range row {
+		row[i] = escapeSpreadsheetCell(cell)
+	}
+	if t.csv != nil {
+		return t.csv.Write(row)
+	}
+	return t.xlsx.WriteRow(row)
+}
+
+func (t *tableExportWriter) Close() error {
+	if t.csv != nil {
+		t.csv.Flush()
+		return t.csv.Error()
+	}
+	return t.xlsx.Close()
+}
+
+// escapeSpreadsheetCell keeps spreadsheet tools from evaluating a cell as a
+// formula by prefixing it with an apostrophe. Cells that already start with
+// apostrophes before a formula character get one more, so
+// unescapeSpreadsheetCell restores every cell exactly.
+func escapeSpreadsheetCell(cell string) string {
+	if startsLikeFormula(strings.TrimLeft(cell, "'")) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// unescapeSpreadsheetCell undoes escapeSpreadsheetCell.
+func unescapeSpreadsheetCell(cell string) string {
+	if strings.HasPrefix(cell, "'") && startsLikeFormula(strings.TrimLeft(cell, "'")) {
+		return cell[1:]
+	}
+	return cell
+}
+
+func startsLikeFormula(cell string) bool {
+	return cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0]))
+}
+
+// jsonExportWriter writes a single JSON array, one record at a time.
+type jsonExportWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (j *jsonExportWriter) Write(record dtos.FAQRecord) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n"
+	if !j.started {
+		prefix = "[\n"
+		j.started = true
+	}
+	_, err = fmt.Fprintf(j.w, "%s%s", prefix, body)
+	return err
+}
+
+func (j *jsonExportWriter) Close() error {
+	closing := "\n]\n"
+	if !j.started {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+// yamlExportWriter writes each record as a one-item sequence; concatenated they
+// form a single top-level sequence.
+type yamlExportWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (y *yamlExportWriter) Write(record dtos.FAQRecord) error {
+	body, err := yaml.Marshal([]dtos.FAQRecord{record})
+	if err != nil {
+		return err
+	}
+	y.started = true
+	_, err = y.w.Write(body)
+	return err
+}
+
+func (y *yamlExportWriter) Close() error {
+	if y.started {
+		return nil
+	}
+	_, err := io.WriteString(y.w, "[]\n")
+	return err
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
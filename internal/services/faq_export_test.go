@@ -0,0 +1,235 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	dtos "github.com/kareemhamed001/faq/internal/DTOs"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+)
+
+// exportCategories has two categories named "International", told apart by
+// their paths.
+func exportCategories() *categoryPaths {
+	shipping, returns := uint(1), uint(2)
+	return newCategoryPaths([]models.Category{
+		{ID: 1, Name: "Shipping"},
+		{ID: 2, Name: "Returns"},
+		{ID: 3, Name: "International", ParentID: &shipping},
+		{ID: 4, Name: "International", ParentID: &returns},
+		{ID: 5, Name: "Customs", ParentID: &shipping},
+	})
+}
+
+func exportFixtures() []models.FAQ {
+	storeID := uint(7)
+	key := "shipping-times"
+	publishAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	expireAt := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return []models.FAQ{
+		{
+			ID:          3,
+			ExternalKey: &key,
+			StoreID:     &storeID,
+			CategoryID:  3,
+			Status:      types.FAQStatusPublished,
+			PublishAt:   &publishAt,
+			ExpireAt:    &expireAt,
+			Translations: []models.Translation{
+				{Language: "ar", Question: "كم يستغرق الشحن؟", Answer: "ثلاثة أيام"},
+				{Language: "en", Question: "How long does shipping take?", Answer: "Three days, \"usually\".\nSometimes more"},
+			},
+		},
+		{
+			ID:         4,
+			IsGlobal:   true,
+			CategoryID: 2,
+			Status:     types.FAQStatusArchived,
+			Translations: []models.Translation{
+				{Language: "en", Question: "Can I return an item?", Answer: "Within 30 days"},
+				{Language: "fr", Question: "=HYPERLINK(\"http://example.com\")", Answer: "'+33 1 23 45 67 89"},
+			},
+		},
+	}
+}
+
+// TestExportImportRoundTrip checks that every export format reads back as the
+// records it was written from, and that an admin import puts each record back
+// in the store (or the global scope) it was exported from.
+func TestExportImportRoundTrip(t *testing.T) {
+	faqs := exportFixtures()
+	categories := exportCategories()
+	want := make([]dtos.FAQRecord, len(faqs))
+	for i := range faqs {
+		want[i] = exportRecord(&faqs[i], categories)
+	}
+
+	formats := []types.FAQFileFormat{types.FAQFileCSV, types.FAQFileXLSX, types.FAQFileJSON, types.FAQFileYAML}
+	for _, format := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			out, err := newFAQExportWriter(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range faqs {
+				if err := out.Write(exportRecord(&faqs[i], categories)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := out.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			records, err := parseImport(bytes.NewReader(buf.Bytes()), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != len(want) {
+				t.Fatalf("got %d records, want %d", len(records), len(want))
+			}
+
+			for i, record := range records {
+				if len(record.errs) > 0 {
+					t.Fatalf("record %d has errors: %+v", i, record.errs)
+				}
+				validateImportRecord(&record, categories, map[string]int{})
+				if len(record.errs) > 0 {
+					t.Fatalf("record %d fails validation: %+v", i, record.errs)
+				}
+				if id, _ := categories.Resolve(record.Category); id != faqs[i].CategoryID {
+					t.Errorf("record %d imported into category %d, want %d", i, id, faqs[i].CategoryID)
+				}
+
+				got := record.FAQRecord
+				if !reflect.DeepEqual(got, want[i]) {
+					t.Errorf("record %d:\n got %+v\nwant %+v", i, got, want[i])
+				}
+
+				if storeID := importStoreID(types.RoleAdmin, nil, &record); !reflect.DeepEqual(storeID, faqs[i].StoreID) {
+					t.Errorf("record %d imported by an admin into store %v, want %v", i, storeID, faqs[i].StoreID)
+				}
+			}
+		})
+	}
+}
+
+func TestSpreadsheetCellEscaping(t *testing.T) {
+	tests := []struct {
+		cell    string
+		escaped string
+	}{
+		{"plain text", "plain text"},
+		{"=1+1", "'=1+1"},
+		{"+33 1 23", "'+33 1 23"},
+		{"-5", "'-5"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"'=1+1", "''=1+1"},
+		{"'quoted", "'quoted"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escapeSpreadsheetCell(tt.cell); got != tt.escaped {
+			t.Errorf("escapeSpreadsheetCell(%q) = %q, want %q", tt.cell, got, tt.escaped)
+		}
+		if got := unescapeSpreadsheetCell(tt.escaped); got != tt.cell {
+			t.Errorf("unescapeSpreadsheetCell(%q) = %q, want %q", tt.escaped, got, tt.cell)
+		}
+	}
+}
+
+func TestExportedFAQID(t *testing.T) {
+	tests := []struct {
+		key    string
+		id     uint
+		want   uint
+		claims bool
+	}{
+		{"faq-12", 12, 12, true},
+		{"faq-12", 0, 0, false},
+		{"faq-12", 7, 0, false},
+		{"shipping-times", 12, 0, false},
+		{"faq-x", 12, 0, false},
+	}
+	for _, tt := range tests {
+		record := importRecord{FAQRecord: dtos.FAQRecord{ID: tt.id, ExternalKey: tt.key}}
+		if id, ok := exportedFAQID(&record); id != tt.want || ok != tt.claims {
+			t.Errorf("exportedFAQID(%q, id %d) = %d, %v, want %d, %v", tt.key, tt.id, id, ok, tt.want, tt.claims)
+		}
+	}
+}
+
+func TestImportStoreIDForMerchants(t *testing.T) {
+	own := uint(5)
+	other := uint(9)
+	record := importRecord{FAQRecord: dtos.FAQRecord{StoreID: &other}}
+
+	if storeID := importStoreID(types.RoleMerchant, &own, &record); storeID == nil || *storeID != own {
+		t.Errorf("merchant import went to store %v, want %d", storeID, own)
+	}
+}
+
+func TestValidateImportRecordStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		valid  bool
+	}{
+		{"", true},
+		{"draft", true},
+		{"published", true},
+		{"live", false},
+	}
+
+	for _, tt := range tests {
+		record := importRecord{
+			FAQRecord: dtos.FAQRecord{
+				Category:     "Shipping",
+				Status:       tt.status,
+				Translations: []dtos.TranslationDTO{{Language: "en", Question: "Q", Answer: "A"}},
+			},
+			row: 2,
+		}
+		validateImportRecord(&record, exportCategories(), map[string]int{})
+		if valid := len(record.errs) == 0; valid != tt.valid {
+			t.Errorf("status %q: valid = %v, want %v (%+v)", tt.status, valid, tt.valid, record.errs)
+		}
+	}
+}
+
+func TestCategoryPathsResolve(t *testing.T) {
+	categories := exportCategories()
+
+	tests := []struct {
+		ref  string
+		want uint // 0 when the reference must be rejected
+	}{
+		{"Shipping", 1},
+		{"Shipping > International", 3},
+		{"returns>international", 4},
+		{"  SHIPPING  >  customs ", 5},
+		{"Customs", 5},
+		{"International", 0},
+		{"Shipping > Returns", 0},
+		{"Gifts", 0},
+	}
+	for _, tt := range tests {
+		id, err := categories.Resolve(tt.ref)
+		if tt.want == 0 {
+			if err == nil {
+				t.Errorf("Resolve(%q) = %d, want an error", tt.ref, id)
+			}
+			continue
+		}
+		if err != nil || id != tt.want {
+			t.Errorf("Resolve(%q) = %d, %v, want %d", tt.ref, id, err, tt.want)
+		}
+	}
+
+	if got := categories.Path(4); got != "Returns > International" {
+		t.Errorf("Path(4) = %q", got)
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxRatedFAQs = 100
+
+// FAQFeedbackInput is a customer's vote on an FAQ. ClientID identifies the
+// voter, so repeated votes replace each other; it is stored only as a hash.
+type FAQFeedbackInput struct {
+	Helpful  bool
+	Comment  string
+	Language string
+	ClientID string
+}
+
+// FAQRating is an FAQ's helpfulness within one store.
+type FAQRating struct {
+	FAQID    uint   `json:"faq_id"`
+	StoreID  uint   `json:"store_id"`
+	Question string `json:"question"`
+	models.FAQFeedbackSummary
+}
+
+// SubmitFeedback records a vote on an FAQ shown on a store's page: one of the
+// store's own live FAQs or a live global one.
+func (s *FAQService) SubmitFeedback(ctx context.Context, storeID, faqId uint, input FAQFeedbackInput) (*models.FAQFeedback, error) {
+	db := s.DB.WithContext(ctx)
+	if _, err := findActiveStore(db, storeID); err != nil {
+		return nil, err
+	}
+
+	var visible int64
+	err := db.Model(&models.FAQ{}).
+		Where("id = ?", faqId).
+		Where("store_id = ? OR is_global = ?", storeID, true).
+		Scopes(liveFAQs(time.Now())).
+		Count(&visible).Error
+	if err != nil {
+		return nil, err
+	}
+	if visible == 0 {
+		return nil, ErrFAQNotFound
+	}
+
+	hash := sha256.Sum256([]byte(input.ClientID))
+	feedback := models.FAQFeedback{
+		FAQID:      faqId,
+		StoreID:    storeID,
+		ClientHash: hex.EncodeToString(hash[:]),
+		Helpful:    input.Helpful,
+		Language:   input.Language,
+	}
+	if comment := strings.TrimSpace(input.Comment); comment != "" {
+		feedback.Comment = &comment
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "faq_id"}, {Name: "store_id"}, {Name: "client_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"helpful", "comment", "language", "updated_at"}),
+	}).Create(&feedback).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &feedback, nil
+}
+
+// ListFeedback returns the votes on an FAQ, newest first. Merchants only see
+// the votes given in their own store.
+func (s *FAQService) ListFeedback(ctx context.Context, faqId uint, role types.UserRole, userId uint, page, pageSize int) ([]models.FAQFeedback, int64, error) {
+	faq, err := s.loadFAQ(ctx, faqId)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.ensureCanViewFAQ(ctx, role, userId, faq); err != nil {
+		return nil, 0, err
+	}
+
+	query, err := s.feedbackQuery(ctx, role, userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	query = query.Where("faq_id = ?", faqId)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	feedback := []models.FAQFeedback{}
+	err = query.Order("updated_at DESC, id DESC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&feedback).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return feedback, total, nil
+}
+
+// LowestRatedFAQs lists the least helpful FAQs per store among those with at
+// least minVotes votes. Merchants get their own store; admins every store
+// unless storeID is given.
+func (s *FAQService) LowestRatedFAQs(ctx context.Context, role types.UserRole, userId uint, storeID *uint, minVotes, limit int) ([]FAQRating, error) {
+	query, err := s.feedbackQuery(ctx, role, userId)
+	if err != nil {
+		return nil, err
+	}
+	if role == types.RoleAdmin && storeID != nil {
+		query = query.Where("faq_feedbacks.store_id = ?", *storeID)
+	}
+
+	if minVotes < 1 {
+		minVotes = 1
+	}
+	if limit <= 0 || limit > maxRatedFAQs {
+		limit = 20
+	}
+
+	ratings := []FAQRating{}
+	err = query.
+		Select(`faq_feedbacks.faq_id, faq_feedbacks.store_id,
+			COUNT(*) FILTER (WHERE faq_feedbacks.helpful) AS helpful,
+			COUNT(*) FILTER (WHERE NOT faq_feedbacks.helpful) AS not_helpful,
+			AVG(CASE WHEN faq_feedbacks.helpful THEN 1.0 ELSE 0.0 END) AS helpfulness,
+			(
+				SELECT t.question FROM translations t
+				WHERE t.faq_id = faq_feedbacks.faq_id AND t.deleted_at IS NULL
+				ORDER BY t.language = 'en' DESC, t.id
+				LIMIT 1
+			) AS question`).
+		Joins("JOIN faqs ON faqs.id = faq_feedbacks.faq_id AND faqs.deleted_at IS NULL").
+		Group("faq_feedbacks.faq_id, faq_feedbacks.store_id").
+		Having("COUNT(*) >= ?", minVotes).
+		Order("helpfulness, not_helpful DESC, faq_feedbacks.store_id, faq_feedbacks.faq_id").
+		Limit(limit).
+		Scan(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// attachFeedback sets the vote summary of each FAQ as seen by the caller.
+// Only merchants and admins get one.
+func (s *FAQService) attachFeedback(ctx context.Context, role types.UserRole, userId uint, faqs ...*models.FAQ) error {
+	if len(faqs) == 0 || (role != types.RoleAdmin && role != types.RoleMerchant) {
+		return nil
+	}
+
+	ids := make([]uint, len(faqs))
+	for i, faq := range faqs {
+		ids[i] = faq.ID
+	}
+
+	query, err := s.feedbackQuery(ctx, role, userId)
+	if err != nil {
+		return err
+	}
+
+	var rows []struct {
+		FAQID uint
+		models.FAQFeedbackSummary
+	}
+	err = query.
+		Select(`faq_id,
+			COUNT(*) FILTER (WHERE helpful) AS helpful,
+			COUNT(*) FILTER (WHERE NOT helpful) AS not_helpful,
+			AVG(CASE WHEN helpful THEN 1.0 ELSE 0.0 END) AS helpfulness`).
+		Where("faq_id IN ?", ids).
+		Group("faq_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	summaries := make(map[uint]models.FAQFeedbackSummary, len(rows))
+	for _, row := range rows {
+		summaries[row.FAQID] = row.FAQFeedbackSummary
+	}
+	for _, faq := range faqs {
+		summary := summaries[faq.ID]
+		faq.Feedback = &summary
+	}
+	return nil
+}
+
+// feedbackQuery scopes votes to the caller: all of them for admins, those
+// given in the merchant's store otherwise.
+func (s *FAQService) feedbackQuery(ctx context.Context, role types.UserRole, userId uint) (*gorm.DB, error) {
+	query := s.DB.WithContext(ctx).Model(&models.FAQFeedback{})
+
+	switch role {
+	case types.RoleAdmin:
+		return query, nil
+	case types.RoleMerchant:
+		return query.Where("faq_feedbacks.store_id IN (SELECT id FROM stores WHERE merchant_id = ?)", userId), nil
+	default:
+		return nil, ErrUnsupportedRole
+	}
+}
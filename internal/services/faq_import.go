@@ -0,0 +1,460 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	dtos "github.com/kareemhamed001/faq/internal/DTOs"
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnsupportedFileFormat = errors.New("unsupported file format")
+	ErrInvalidImportFile     = errors.New("invalid import file")
+)
+
+// errImportRollback aborts the import transaction without reporting an error.
+var errImportRollback = errors.New("import rolled back")
+
+// ImportOptions control how an import file is applied. DryRun validates and
+// reports without saving; Atomic saves nothing unless every row succeeds.
+type ImportOptions struct {
+	Format types.FAQFileFormat
+	DryRun bool
+	Atomic bool
+}
+
+type ImportRowError struct {
+	Row         int    `json:"row"`
+	ExternalKey string `json:"external_key,omitempty"`
+	Field       string `json:"field,omitempty"`
+	Message     string `json:"message"`
+}
+
+type ImportResult struct {
+	DryRun    bool             `json:"dry_run"`
+	Atomic    bool             `json:"atomic"`
+	Committed bool             `json:"committed"`
+	Total     int              `json:"total"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Failed    int              `json:"failed"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+// importRecord is a parsed FAQ with the file rows it came from. Rows are CSV
+// line or spreadsheet row numbers, or 1-based item positions for JSON and YAML.
+type importRecord struct {
+	dtos.FAQRecord
+	row             int
+	translationRows []int
+	errs            []ImportRowError
+}
+
+// ImportFAQs creates or updates FAQs from a CSV, XLSX, JSON or YAML file. FAQs
+// with an external_key that already exists in the caller's scope are updated;
+// all others are created as drafts. Each FAQ is saved in its own savepoint so one
+// bad row does not affect the rest unless the import is atomic.
+func (s *FAQService) ImportFAQs(ctx context.Context, r io.Reader, opts ImportOptions, role types.UserRole, userId uint) (*ImportResult, error) {
+	records, err := parseImport(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		DryRun: opts.DryRun,
+		Atomic: opts.Atomic,
+		Total:  len(records),
+		Errors: []ImportRowError{},
+	}
+
+	var saved []uint
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var merchantStoreID *uint
+		switch role {
+		case types.RoleAdmin:
+		case types.RoleMerchant:
+			if s.RequireVerifiedMerchants {
+				if err := s.assertEmailVerified(tx, userId); err != nil {
+					return err
+				}
+			}
+			id, err := s.getMerchantStoreID(tx, userId)
+			if err != nil {
+				return err
+			}
+			merchantStoreID = &id
+		default:
+			return ErrUnsupportedRole
+		}
+
+		categories, err := loadCategoryPaths(tx)
+		if err != nil {
+			return err
+		}
+
+		seenKeys := make(map[string]int)
+		for i := range records {
+			record := &records[i]
+			validateImportRecord(record, categories, seenKeys)
+			if len(record.errs) > 0 {
+				result.Failed++
+				result.Errors = append(result.Errors, record.errs...)
+				continue
+			}
+
+			var id uint
+			created := false
+			err := tx.Transaction(func(itx *gorm.DB) error {
+				var err error
+				id, created, err = s.upsertImportRecord(itx, record, categories, importStoreID(role, merchantStoreID, record), role, userId)
+				return err
+			})
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, ImportRowError{Row: record.row, ExternalKey: record.ExternalKey, Message: err.Error()})
+				continue
+			}
+			saved = append(saved, id)
+			if created {
+				result.Created++
+			} else {
+				result.Updated++
+			}
+		}
+
+		if opts.DryRun || (opts.Atomic && result.Failed > 0) {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return nil, err
+	}
+
+	result.Committed = err == nil
+	if result.Committed {
+		s.indexer.Sync(ctx, saved...)
+	}
+	return result, nil
+}
+
+// importStoreID returns the store a record is imported into, or nil for
+// global FAQs. Merchants always import into their own store; admins import
+// into the record's store_id, so exported store FAQs go back where they came
+// from.
+func importStoreID(role types.UserRole, merchantStoreID *uint, record *importRecord) *uint {
+	if role == types.RoleMerchant {
+		return merchantStoreID
+	}
+	return record.StoreID
+}
+
+// upsertImportRecord saves one validated record into the given store (nil for
+// global FAQs) and returns the FAQ's id and whether it was newly created.
+func (s *FAQService) upsertImportRecord(tx *gorm.DB, record *importRecord, categories *categoryPaths, storeID *uint, role types.UserRole, userId uint) (uint, bool, error) {
+	categoryID, err := categories.Resolve(record.Category)
+	if err != nil {
+		return 0, false, err
+	}
+	schedule := FAQSchedule{PublishAt: record.PublishAt, ExpireAt: record.ExpireAt}
+
+	if role == types.RoleAdmin && storeID != nil {
+		var store models.Store
+		if err := tx.Select("id").First(&store, *storeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, false, fmt.Errorf("store %d not found", *storeID)
+			}
+			return 0, false, err
+		}
+	}
+
+	// Merchants go through the review workflow: their FAQs keep the status
+	// createFAQ or updateFAQ gave them, whatever the file says.
+	id, created, err := s.saveImportRecord(tx, record, categoryID, schedule, storeID, role, userId)
+	if err != nil || record.Status == "" || role != types.RoleAdmin {
+		return id, created, err
+	}
+	return id, created, tx.Model(&models.FAQ{}).Where("id = ?", id).Update("status", record.Status).Error
+}
+
+func (s *FAQService) saveImportRecord(tx *gorm.DB, record *importRecord, categoryID uint, schedule FAQSchedule, storeID *uint, role types.UserRole, userId uint) (uint, bool, error) {
+	if record.ExternalKey == "" {
+		id, err := s.createFAQ(tx, userId, categoryID, record.Translations, schedule, role, storeID, nil)
+		return id, true, err
+	}
+
+	inScope := func() *gorm.DB {
+		query := tx.Preload("Translations")
+		if storeID != nil {
+			return query.Where("store_id = ?", *storeID)
+		}
+		return query.Where("store_id IS NULL")
+	}
+
+	var existing models.FAQ
+	err := inScope().Where("external_key = ?", record.ExternalKey).First(&existing).Error
+	if id, ok := exportedFAQID(record); ok && errors.Is(err, gorm.ErrRecordNotFound) {
+		// Exports name FAQs without an external_key "faq-<id>"; importing the
+		// file back updates them and keeps the key from then on.
+		err = inScope().Where("id = ? AND external_key IS NULL", id).First(&existing).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		key := record.ExternalKey
+		id, err := s.createFAQ(tx, userId, categoryID, record.Translations, schedule, role, storeID, &key)
+		return id, true, err
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Model(&existing).Updates(map[string]interface{}{
+		"external_key": record.ExternalKey,
+		"publish_at":   schedule.PublishAt,
+		"expire_at":    schedule.ExpireAt,
+	}).Error; err != nil {
+		return 0, false, err
+	}
+
+	return existing.ID, false, s.updateFAQ(tx, &existing, userId, &categoryID, record.Translations, role)
+}
+
+// exportedFAQID parses the "faq-<id>" key given to exported FAQs that have no
+// external_key of their own. The record must carry the same id, so a key that
+// merely looks like one, chosen by hand or exported from another environment
+// without its ids, stays an ordinary key.
+func exportedFAQID(record *importRecord) (uint, bool) {
+	key := record.ExternalKey
+	id, err := strconv.ParseUint(strings.TrimPrefix(key, exportedKeyPrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(key, exportedKeyPrefix) || record.ID == 0 || uint(id) != record.ID {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func validateImportRecord(record *importRecord, categories *categoryPaths, seenKeys map[string]int) {
+	fail := func(row int, field, message string) {
+		record.errs = append(record.errs, ImportRowError{Row: row, ExternalKey: record.ExternalKey, Field: field, Message: message})
+	}
+
+	if record.ExternalKey != "" {
+		if first, ok := seenKeys[record.ExternalKey]; ok {
+			fail(record.row, "external_key", fmt.Sprintf("duplicate external_key, first used on row %d", first))
+		} else {
+			seenKeys[record.ExternalKey] = record.row
+		}
+	}
+
+	if strings.TrimSpace(record.Category) == "" {
+		fail(record.row, "category", "category is required")
+	} else if _, err := categories.Resolve(record.Category); err != nil {
+		fail(record.row, "category", err.Error())
+	}
+
+	if record.Status != "" && !isFAQStatus(types.FAQStatus(record.Status)) {
+		fail(record.row, "status", fmt.Sprintf("status must be one of %s, %s, %s or %s",
+			types.FAQStatusDraft, types.FAQStatusInReview, types.FAQStatusPublished, types.FAQStatusArchived))
+	}
+
+	if err := (FAQSchedule{PublishAt: record.PublishAt, ExpireAt: record.ExpireAt}).validate(); err != nil {
+		fail(record.row, "expire_at", err.Error())
+	}
+
+	if len(record.Translations) == 0 {
+		fail(record.row, "translations", "at least one translation is required")
+	}
+
+	languages := make(map[string]bool)
+	for i, t := range record.Translations {
+		row := record.row
+		if i < len(record.translationRows) {
+			row = record.translationRows[i]
+		}
+		switch {
+		case strings.TrimSpace(t.Language) == "":
+			fail(row, "language", "language is required")
+		case languages[t.Language]:
+			fail(row, "language", fmt.Sprintf("duplicate translation for language %q", t.Language))
+		}
+		languages[t.Language] = true
+		if strings.TrimSpace(t.Question) == "" {
+			fail(row, "question", "question is required")
+		}
+		if strings.TrimSpace(t.Answer) == "" {
+			fail(row, "answer", "answer is required")
+		}
+	}
+}
+
+func parseImport(r io.Reader, format types.FAQFileFormat) ([]importRecord, error) {
+	switch format {
+	case types.FAQFileCSV:
+		reader := csv.NewReader(r)
+		reader.TrimLeadingSpace = true
+		reader.FieldsPerRecord = -1
+		return parseImportTable(func() ([]string, int, error) {
+			row, err := reader.Read()
+			if err != nil {
+				return nil, 0, err
+			}
+			line, _ := reader.FieldPos(0)
+			return row, line, nil
+		})
+	case types.FAQFileXLSX:
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := helpers.ReadXLSXRows(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		next := 0
+		return parseImportTable(func() ([]string, int, error) {
+			for next < len(rows) {
+				row := rows[next]
+				next++
+				if strings.TrimSpace(strings.Join(row, "")) != "" {
+					return row, next, nil
+				}
+			}
+			return nil, 0, io.EOF
+		})
+	case types.FAQFileJSON, types.FAQFileYAML:
+		var items []dtos.FAQRecord
+		var err error
+		if format == types.FAQFileJSON {
+			err = json.NewDecoder(r).Decode(&items)
+		} else {
+			err = yaml.NewDecoder(r).Decode(&items)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+
+		records := make([]importRecord, 0, len(items))
+		for i, item := range items {
+			records = append(records, importRecord{FAQRecord: item, row: i + 1})
+		}
+		return records, nil
+	default:
+		return nil, ErrUnsupportedFileFormat
+	}
+}
+
+// parseImportTable reads CSV or spreadsheet rows, one per FAQ and language,
+// with the columns id, external_key, store_id, category, status, language,
+// question, answer, publish_at and expire_at. Rows sharing an external_key are merged into one
+// FAQ; unknown columns are ignored. next returns each row with its line number.
+func parseImportTable(next func() ([]string, int, error)) ([]importRecord, error) {
+	header, _, err := next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"category", "language", "question", "answer"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidImportFile, required)
+		}
+	}
+
+	var records []importRecord
+	byKey := make(map[string]int)
+	for {
+		row, line, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(unescapeSpreadsheetCell(row[i]))
+			}
+			return ""
+		}
+
+		translation := dtos.TranslationDTO{
+			Language: field("language"),
+			Question: field("question"),
+			Answer:   field("answer"),
+		}
+
+		key := field("external_key")
+		if i, ok := byKey[key]; ok && key != "" {
+			records[i].Translations = append(records[i].Translations, translation)
+			records[i].translationRows = append(records[i].translationRows, line)
+			continue
+		}
+
+		record := importRecord{
+			FAQRecord: dtos.FAQRecord{
+				ExternalKey:  key,
+				Category:     field("category"),
+				Status:       field("status"),
+				Translations: []dtos.TranslationDTO{translation},
+			},
+			row:             line,
+			translationRows: []int{line},
+		}
+		if value := field("id"); value != "" {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				record.errs = append(record.errs, ImportRowError{Row: line, ExternalKey: key, Field: "id", Message: "must be a number"})
+			} else {
+				record.ID = uint(id)
+			}
+		}
+		if value := field("store_id"); value != "" {
+			storeID, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				record.errs = append(record.errs, ImportRowError{Row: line, ExternalKey: key, Field: "store_id", Message: "must be a number"})
+			} else {
+				id := uint(storeID)
+				record.StoreID = &id
+			}
+		}
+		for _, bound := range []struct {
+			name   string
+			target **time.Time
+		}{
+			{"publish_at", &record.PublishAt},
+			{"expire_at", &record.ExpireAt},
+		} {
+			value := field(bound.name)
+			if value == "" {
+				continue
+			}
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				record.errs = append(record.errs, ImportRowError{Row: line, ExternalKey: key, Field: bound.name, Message: "must be an RFC 3339 timestamp"})
+				continue
+			}
+			*bound.target = &parsed
+		}
+
+		if key != "" {
+			byKey[key] = len(records)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
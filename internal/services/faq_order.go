@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrStoreIDRequired = errors.New("store_id is required")
+	ErrInvalidFAQOrder = errors.New("faq_ids must list distinct faqs of the category shown in the store")
+)
+
+// ReorderFAQs sets the order in which a store shows the FAQs of a category:
+// faqIDs get positions 1, 2, ... and the category's other FAQs lose theirs,
+// falling back to newest first after the ordered ones. Merchants order their
+// own store; admins name the store. The ids may be the store's own FAQs and
+// live global ones.
+func (s *FAQService) ReorderFAQs(ctx context.Context, role types.UserRole, userId uint, storeID *uint, categoryId uint, faqIDs []uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var targetStoreID uint
+		switch role {
+		case types.RoleAdmin:
+			if storeID == nil {
+				return ErrStoreIDRequired
+			}
+			targetStoreID = *storeID
+		case types.RoleMerchant:
+			id, err := s.getMerchantStoreID(tx, userId)
+			if err != nil {
+				return err
+			}
+			targetStoreID = id
+		default:
+			return ErrUnsupportedRole
+		}
+
+		// Locking the store makes concurrent reorders apply one after the other.
+		var store models.Store
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&store, targetStoreID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrStoreNotFound
+			}
+			return err
+		}
+
+		if err := s.assertCategoryExists(tx, categoryId); err != nil {
+			return err
+		}
+
+		seen := make(map[uint]bool, len(faqIDs))
+		for _, id := range faqIDs {
+			if seen[id] {
+				return ErrInvalidFAQOrder
+			}
+			seen[id] = true
+		}
+
+		if len(faqIDs) > 0 {
+			var found int64
+			err := tx.Model(&models.FAQ{}).
+				Where("id IN ? AND category_id = ?", faqIDs, categoryId).
+				Where("store_id = ? OR (is_global = ? AND id IN (?))", targetStoreID, true,
+					tx.Model(&models.FAQ{}).Select("id").Scopes(liveFAQs(time.Now()))).
+				Count(&found).Error
+			if err != nil {
+				return err
+			}
+			if int(found) != len(faqIDs) {
+				return ErrInvalidFAQOrder
+			}
+		}
+
+		err := tx.Where("store_id = ? AND faq_id IN (?)", targetStoreID,
+			tx.Model(&models.FAQ{}).Unscoped().Select("id").Where("category_id = ?", categoryId)).
+			Delete(&models.FAQPosition{}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(faqIDs) == 0 {
+			return nil
+		}
+		positions := make([]models.FAQPosition, len(faqIDs))
+		for i, id := range faqIDs {
+			positions[i] = models.FAQPosition{StoreID: targetStoreID, FAQID: id, Position: i + 1}
+		}
+		return tx.Create(&positions).Error
+	})
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	dtos "github.com/kareemhamed001/faq/internal/DTOs"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// RevisionDiff compares two revisions of an FAQ, language by language.
+type RevisionDiff struct {
+	From         int               `json:"from"`
+	To           int               `json:"to"`
+	CategoryID   *FieldChange      `json:"category_id,omitempty"`
+	Translations []TranslationDiff `json:"translations"`
+}
+
+type TranslationDiff struct {
+	Language string       `json:"language"`
+	Status   string       `json:"status"` // "added", "removed", "changed" or "unchanged"
+	Question *FieldChange `json:"question,omitempty"`
+	Answer   *FieldChange `json:"answer,omitempty"`
+}
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// ListRevisions returns the history of an FAQ, newest first. Deleted FAQs keep
+// their history so it can be inspected before restoring them from the trash.
+func (s *FAQService) ListRevisions(ctx context.Context, faqId uint, role types.UserRole, userId uint, page, pageSize int) ([]models.FAQRevision, int64, error) {
+	db := s.DB.WithContext(ctx)
+	if err := s.ensureCanManageRevisions(db, faqId, role, userId); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := db.Model(&models.FAQRevision{}).Where("faq_id = ?", faqId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []models.FAQRevision
+	err := query.Order("revision DESC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&revisions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+// DiffRevisions compares revision rev with revision against. When against is
+// zero the previous revision is used; the first revision is compared with an
+// empty FAQ.
+func (s *FAQService) DiffRevisions(ctx context.Context, faqId uint, rev, against int, role types.UserRole, userId uint) (*RevisionDiff, error) {
+	db := s.DB.WithContext(ctx)
+	if err := s.ensureCanManageRevisions(db, faqId, role, userId); err != nil {
+		return nil, err
+	}
+
+	to, err := loadRevision(db, faqId, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	if against == 0 {
+		against = rev - 1
+	}
+	from := &models.FAQRevision{}
+	if against > 0 {
+		from, err = loadRevision(db, faqId, against)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return diffRevisions(from, to), nil
+}
+
+// RestoreRevision rolls an FAQ back to the category and translation set of an
+// earlier revision. The rollback itself is recorded as a new revision.
+func (s *FAQService) RestoreRevision(ctx context.Context, faqId uint, rev int, role types.UserRole, userId uint) (*models.FAQ, error) {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		faq := models.FAQ{}
+		if err := tx.Preload("Translations").First(&faq, faqId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFAQNotFound
+			}
+			return err
+		}
+
+		if err := s.ensureCanManageFAQ(tx, role, userId, &faq); err != nil {
+			return err
+		}
+
+		revision, err := loadRevision(tx, faqId, rev)
+		if err != nil {
+			return err
+		}
+
+		if revision.CategoryID != faq.CategoryID {
+			if err := s.assertCategoryExists(tx, revision.CategoryID); err != nil {
+				return err
+			}
+			if err := tx.Model(&faq).Update("category_id", revision.CategoryID).Error; err != nil {
+				return err
+			}
+		}
+
+		translations := make([]dtos.TranslationDTO, 0, len(revision.Translations))
+		for _, t := range revision.Translations {
+			translations = append(translations, dtos.TranslationDTO{
+				Language: t.Language,
+				Question: t.Question,
+				Answer:   t.Answer,
+			})
+		}
+		if err := syncTranslations(tx, &faq, translations); err != nil {
+			return err
+		}
+
+		if err := requeueForReview(tx, role, &faq); err != nil {
+			return err
+		}
+
+		return recordFAQRevision(tx, faq.ID, userId, types.FAQRevisionRestored, &revision.Revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.indexer.Sync(ctx, faqId)
+
+	return s.loadFAQ(ctx, faqId)
+}
+
+func (s *FAQService) ensureCanManageRevisions(db *gorm.DB, faqId uint, role types.UserRole, userId uint) error {
+	faq := models.FAQ{}
+	if err := db.Unscoped().First(&faq, faqId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrFAQNotFound
+		}
+		return err
+	}
+
+	return s.ensureCanManageFAQ(db, role, userId, &faq)
+}
+
+// recordFAQRevision snapshots the current category and live translations of
+// an FAQ as its next revision. The FAQ row is locked so concurrent edits get
+// consecutive revision numbers.
+func recordFAQRevision(tx *gorm.DB, faqId, authorId uint, action types.FAQRevisionAction, restoredFrom *int) error {
+	faq := models.FAQ{}
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "category_id").First(&faq, faqId).Error; err != nil {
+		return err
+	}
+
+	var translations []models.Translation
+	if err := tx.Where("faq_id = ?", faqId).Order("language").Find(&translations).Error; err != nil {
+		return err
+	}
+
+	var last int
+	if err := tx.Model(&models.FAQRevision{}).
+		Where("faq_id = ?", faqId).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+
+	revision := models.FAQRevision{
+		FAQID:        faq.ID,
+		Revision:     last + 1,
+		Action:       action,
+		CategoryID:   faq.CategoryID,
+		Translations: models.RevisionTranslations{},
+		RestoredFrom: restoredFrom,
+	}
+	if authorId != 0 {
+		revision.AuthorID = &authorId
+	}
+	for _, t := range translations {
+		revision.Translations = append(revision.Translations, models.RevisionTranslation{
+			Language: t.Language,
+			Question: t.Question,
+			Answer:   t.Answer,
+		})
+	}
+
+	return tx.Create(&revision).Error
+}
+
+func loadRevision(db *gorm.DB, faqId uint, rev int) (*models.FAQRevision, error) {
+	var revision models.FAQRevision
+	err := db.Where("faq_id = ? AND revision = ?", faqId, rev).First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func diffRevisions(from, to *models.FAQRevision) *RevisionDiff {
+	diff := &RevisionDiff{
+		From:         from.Revision,
+		To:           to.Revision,
+		Translations: []TranslationDiff{},
+	}
+	if from.CategoryID != to.CategoryID {
+		diff.CategoryID = &FieldChange{From: from.CategoryID, To: to.CategoryID}
+	}
+
+	before := make(map[string]models.RevisionTranslation)
+	for _, t := range from.Translations {
+		before[t.Language] = t
+	}
+	after := make(map[string]models.RevisionTranslation)
+	for _, t := range to.Translations {
+		after[t.Language] = t
+	}
+
+	languages := make([]string, 0, len(before)+len(after))
+	for lang := range before {
+		languages = append(languages, lang)
+	}
+	for lang := range after {
+		if _, ok := before[lang]; !ok {
+			languages = append(languages, lang)
+		}
+	}
+	sort.Strings(languages)
+
+	for _, lang := range languages {
+		old, hadOld := before[lang]
+		cur, hasCur := after[lang]
+
+		entry := TranslationDiff{Language: lang, Status: "unchanged"}
+		switch {
+		case !hadOld:
+			entry.Status = "added"
+			entry.Question = &FieldChange{From: nil, To: cur.Question}
+			entry.Answer = &FieldChange{From: nil, To: cur.Answer}
+		case !hasCur:
+			entry.Status = "removed"
+			entry.Question = &FieldChange{From: old.Question, To: nil}
+			entry.Answer = &FieldChange{From: old.Answer, To: nil}
+		default:
+			if old.Question != cur.Question {
+				entry.Status = "changed"
+				entry.Question = &FieldChange{From: old.Question, To: cur.Question}
+			}
+			if old.Answer != cur.Answer {
+				entry.Status = "changed"
+				entry.Answer = &FieldChange{From: old.Answer, To: cur.Answer}
+			}
+		}
+		diff.Translations = append(diff.Translations, entry)
+	}
+
+	return diff
+}
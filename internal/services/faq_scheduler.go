@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/events"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+// FAQScheduler announces published FAQs whose publish window opens or closes.
+// The last checked instant is kept in settings, so windows that passed while
+// the server was down are announced on the next run.
+type FAQScheduler struct {
+	DB       *gorm.DB
+	settings *SettingsService
+	bus      *events.Bus
+}
+
+func NewFAQScheduler(DB *gorm.DB, settings *SettingsService, bus *events.Bus) *FAQScheduler {
+	return &FAQScheduler{DB: DB, settings: settings, bus: bus}
+}
+
+// Run publishes an event for every publish_at and expire_at that passed since
+// the previous run. The first run only records the current time.
+func (s *FAQScheduler) Run(ctx context.Context, now time.Time) error {
+	since, err := s.settings.GetTime(ctx, SettingFAQSchedulerCheckedAt)
+	if err != nil {
+		return err
+	}
+
+	if !since.IsZero() {
+		var published []models.FAQ
+		err := s.DB.WithContext(ctx).
+			Where("status = ? AND publish_at > ? AND publish_at <= ?", types.FAQStatusPublished, since, now).
+			Where("expire_at IS NULL OR expire_at > publish_at").
+			Order("publish_at").
+			Find(&published).Error
+		if err != nil {
+			return err
+		}
+
+		var expired []models.FAQ
+		err = s.DB.WithContext(ctx).
+			Where("status = ? AND expire_at > ? AND expire_at <= ?", types.FAQStatusPublished, since, now).
+			Order("expire_at").
+			Find(&expired).Error
+		if err != nil {
+			return err
+		}
+
+		for _, faq := range published {
+			s.bus.Publish(events.Event{Type: events.FAQPublished, FAQID: faq.ID, StoreID: faq.StoreID, OccurredAt: *faq.PublishAt})
+		}
+		for _, faq := range expired {
+			s.bus.Publish(events.Event{Type: events.FAQExpired, FAQID: faq.ID, StoreID: faq.StoreID, OccurredAt: *faq.ExpireAt})
+		}
+	}
+
+	return s.settings.SetTime(ctx, SettingFAQSchedulerCheckedAt, now)
+}
+
+// Start runs the scheduler every interval until stop is closed.
+func (s *FAQScheduler) Start(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				if err := s.Run(context.Background(), now); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	dtos "github.com/kareemhamed001/faq/internal/DTOs"
 	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/search"
 	"github.com/kareemhamed001/faq/internal/types"
 	"gorm.io/gorm"
 )
@@ -16,37 +19,58 @@ var (
 	ErrStoreNotFound    = errors.New("store not found for merchant")
 	ErrUnauthorizedFAQ  = errors.New("unauthorized to access faq")
 	ErrUnsupportedRole  = errors.New("role not permitted for this action")
+	ErrInvalidFAQStatus = errors.New("invalid faq status")
+	ErrInvalidFAQAction = errors.New("faq status does not allow this action")
+	ErrInvalidSchedule  = errors.New("expire_at must be after publish_at")
+	ErrInvalidFAQWindow = errors.New("invalid faq window")
 )
 
-type FAQService struct {
-	DB *gorm.DB
+// FAQFilter narrows the FAQ listing. Window is "live", "scheduled" or
+// "expired" and compares the publish window with the current time.
+type FAQFilter struct {
+	Search     string
+	Status     types.FAQStatus
+	Window     string
+	CategoryID *uint // includes the category's subcategories
 }
 
-func NewFAQService(DB *gorm.DB) *FAQService {
-	return &FAQService{DB: DB}
+// FAQSchedule is the optional publish window of an FAQ. A nil bound is open.
+type FAQSchedule struct {
+	PublishAt *time.Time
+	ExpireAt  *time.Time
 }
 
-func (s *FAQService) GetAllFAQs(ctx context.Context, search string, role types.UserRole, userId uint, page, pageSize int, sortDir string, language string) ([]models.FAQ, int64, error) {
+// faqTransitions lists, per workflow action, the statuses it may start from
+// and the resulting status. Who may act on an FAQ at all is decided by
+// ensureCanManageFAQ: merchants only reach their own store's FAQs, so global
+// FAQs are only ever approved by admins.
+var faqTransitions = map[types.FAQTransition]struct {
+	from []types.FAQStatus
+	to   types.FAQStatus
+}{
+	types.FAQTransitionSubmit:    {from: []types.FAQStatus{types.FAQStatusDraft}, to: types.FAQStatusInReview},
+	types.FAQTransitionApprove:   {from: []types.FAQStatus{types.FAQStatusInReview}, to: types.FAQStatusPublished},
+	types.FAQTransitionReject:    {from: []types.FAQStatus{types.FAQStatusInReview}, to: types.FAQStatusDraft},
+	types.FAQTransitionArchive:   {from: []types.FAQStatus{types.FAQStatusPublished}, to: types.FAQStatusArchived},
+	types.FAQTransitionUnarchive: {from: []types.FAQStatus{types.FAQStatusArchived}, to: types.FAQStatusDraft},
+}
 
-	faqQuery := s.DB.WithContext(ctx).
-		Model(&models.FAQ{}).
-		Preload("Category").
-		Preload("Translations")
+type FAQService struct {
+	DB                       *gorm.DB
+	RequireVerifiedMerchants bool
+	indexer                  *FAQIndexer
+}
 
-	if search != "" {
-		faqQuery = faqQuery.Joins("JOIN translations ON translations.faq_id = faqs.id").
-			Where("translations.question ILIKE ? OR translations.answer ILIKE ?", "%"+search+"%", "%"+search+"%")
-	}
+func NewFAQService(DB *gorm.DB, requireVerifiedMerchants bool, indexer *FAQIndexer) *FAQService {
+	return &FAQService{DB: DB, RequireVerifiedMerchants: requireVerifiedMerchants, indexer: indexer}
+}
 
-	switch role {
-	case types.RoleAdmin:
-		// Admin sees everything
-	case types.RoleMerchant:
-		faqQuery = faqQuery.Where("faqs.store_id IN (SELECT id FROM stores WHERE merchant_id = ?)", userId)
-	default:
-		return nil, 0, ErrUnsupportedRole
-	}
+func (s *FAQService) GetAllFAQs(ctx context.Context, filter FAQFilter, role types.UserRole, userId uint, page, pageSize int, sortDir string, language string) ([]models.FAQ, int64, error) {
 
+	faqQuery, err := s.filterFAQs(ctx, filter, role, userId)
+	if err != nil {
+		return nil, 0, err
+	}
 	if page < 1 {
 		page = 1
 	}
@@ -60,12 +84,40 @@ func (s *FAQService) GetAllFAQs(ctx context.Context, search string, role types.U
 	}
 
 	var total int64
-	if err := faqQuery.Distinct("faqs.id").Count(&total).Error; err != nil {
+	if err := faqQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	columns := []string{"faqs.*"}
+	if sortDir == "popular" {
+		// Merchants rank by views in their own store, admins by all views.
+		views := s.DB.Table("faq_view_counts").Select("faq_id, SUM(views) AS views").Group("faq_id")
+		if role == types.RoleMerchant {
+			views = views.Where("store_id IN (SELECT id FROM stores WHERE merchant_id = ?)", userId)
+		}
+		faqQuery = faqQuery.Joins("LEFT JOIN (?) AS popularity ON popularity.faq_id = faqs.id", views)
+		columns = append(columns, "COALESCE(popularity.views, 0) AS views")
+		order = "views DESC, " + order
+	}
+
+	faqQuery = faqQuery.Preload("Category.Translations")
+	if filter.Search != "" {
+		// Most relevant first, with a highlighted snippet of each answer.
+		columns = append(columns, "hits.search_rank")
+		faqQuery = faqQuery.
+			Preload("Translations", func(db *gorm.DB) *gorm.DB {
+				return db.Select("translations.*, ts_headline(faq_ts_config(language), answer, faq_tsquery(?), 'MaxFragments=2, MaxWords=30, MinWords=10') AS snippet", filter.Search)
+			})
+		if sortDir != "popular" {
+			order = "hits.search_rank DESC, " + order
+		}
+	} else {
+		faqQuery = faqQuery.Preload("Translations")
+	}
+
 	var faqs []models.FAQ
-	err := faqQuery.Distinct("faqs.*").
+	err = faqQuery.
+		Select(strings.Join(columns, ", ")).
 		Order(order).
 		Limit(pageSize).
 		Offset((page - 1) * pageSize).
@@ -75,13 +127,106 @@ func (s *FAQService) GetAllFAQs(ctx context.Context, search string, role types.U
 	}
 
 	// Filter translations by language, with fallback
+	listed := make([]*models.FAQ, len(faqs))
 	for i := range faqs {
 		faqs[i].Translations = s.filterTranslations(faqs[i].Translations, language)
+		localizeFAQCategory(&faqs[i], language)
+		listed[i] = &faqs[i]
+	}
+
+	if err := s.attachFeedback(ctx, role, userId, listed...); err != nil {
+		return nil, 0, err
 	}
 
 	return faqs, total, nil
 }
 
+// filterFAQs scopes an FAQ query to the caller (admins see everything,
+// merchants their own store) and applies the listing filter. Searching goes
+// through the search index and joins its matches as "hits", so callers can
+// order by hits.search_rank.
+func (s *FAQService) filterFAQs(ctx context.Context, filter FAQFilter, role types.UserRole, userId uint) (*gorm.DB, error) {
+	db := s.DB.WithContext(ctx)
+	faqQuery := db.Model(&models.FAQ{})
+
+	if filter.Status != "" {
+		if !isFAQStatus(filter.Status) {
+			return nil, ErrInvalidFAQStatus
+		}
+		faqQuery = faqQuery.Where("faqs.status = ?", filter.Status)
+	}
+
+	now := time.Now()
+	switch filter.Window {
+	case "":
+	case "live":
+		faqQuery = faqQuery.Scopes(liveFAQs(now))
+	case "scheduled":
+		faqQuery = faqQuery.Where("faqs.publish_at > ?", now)
+	case "expired":
+		faqQuery = faqQuery.Where("faqs.expire_at <= ?", now)
+	default:
+		return nil, ErrInvalidFAQWindow
+	}
+
+	var searchFilter search.Filter
+	if filter.CategoryID != nil {
+		categoryIDs, err := categorySubtreeIDs(db, *filter.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if len(categoryIDs) == 0 {
+			return nil, ErrCategoryNotFound
+		}
+		faqQuery = faqQuery.Where("faqs.category_id IN ?", categoryIDs)
+		searchFilter.CategoryIDs = categoryIDs
+	}
+
+	switch role {
+	case types.RoleAdmin:
+		// Admin sees everything
+	case types.RoleMerchant:
+		faqQuery = faqQuery.Where("faqs.store_id IN (SELECT id FROM stores WHERE merchant_id = ?)", userId)
+		if filter.Search != "" {
+			if err := db.Model(&models.Store{}).Where("merchant_id = ?", userId).Pluck("id", &searchFilter.StoreIDs).Error; err != nil {
+				return nil, err
+			}
+			if len(searchFilter.StoreIDs) == 0 {
+				return faqQuery.Where("1 = 0"), nil
+			}
+		}
+	default:
+		return nil, ErrUnsupportedRole
+	}
+
+	if filter.Search != "" {
+		hits, err := s.indexer.Search(ctx, search.Query{Text: filter.Search, Filter: searchFilter})
+		if err != nil {
+			return nil, err
+		}
+		faqQuery = joinSearchHits(faqQuery, hits)
+	}
+
+	return faqQuery, nil
+}
+
+// joinSearchHits joins search results as hits(faq_id, search_rank). Without
+// hits the query matches nothing.
+func joinSearchHits(db *gorm.DB, hits []search.Hit) *gorm.DB {
+	if len(hits) == 0 {
+		return db.Where("1 = 0")
+	}
+
+	rows := make([]string, len(hits))
+	args := make([]interface{}, 0, 2*len(hits))
+	for i, hit := range hits {
+		rows[i] = "(?::bigint, ?::float8)"
+		args = append(args, hit.FAQID, hit.Score)
+	}
+
+	return db.Joins("JOIN (VALUES "+strings.Join(rows, ", ")+") AS hits(faq_id, search_rank) ON hits.faq_id = faqs.id", args...)
+}
+
 func (s *FAQService) GetFAQByID(ctx context.Context, id uint, role types.UserRole, userId uint, language string, includeAllTranslations bool) (*models.FAQ, error) {
 	faq, err := s.loadFAQ(ctx, id)
 	if err != nil {
@@ -96,63 +241,71 @@ func (s *FAQService) GetFAQByID(ctx context.Context, id uint, role types.UserRol
 	if !includeAllTranslations {
 		faq.Translations = s.filterTranslations(faq.Translations, language)
 	}
+	localizeFAQCategory(faq, language)
+
+	if err := s.attachFeedback(ctx, role, userId, faq); err != nil {
+		return nil, err
+	}
 
 	return faq, nil
 }
 
-func (s *FAQService) CreateFAQ(ctx context.Context, userId, categoryId uint, translations []dtos.TranslationDTO, role types.UserRole) (*models.FAQ, error) {
+func (s *FAQService) CreateFAQ(ctx context.Context, userId, categoryId uint, translations []dtos.TranslationDTO, schedule FAQSchedule, role types.UserRole) (*models.FAQ, error) {
+	if err := schedule.validate(); err != nil {
+		return nil, err
+	}
+
 	var createdFAQID uint
 
 	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := s.assertCategoryExists(tx, categoryId); err != nil {
+		id, err := s.createFAQ(tx, userId, categoryId, translations, schedule, role, nil, nil)
+		if err != nil {
 			return err
 		}
 
-		faq := models.FAQ{
-			CategoryID: categoryId,
-			IsGlobal:   false,
-		}
+		createdFAQID = id
+		return nil
+	})
 
-		switch role {
-		case types.RoleAdmin:
-			faq.IsGlobal = true
-		case types.RoleMerchant:
-			storeID, err := s.getMerchantStoreID(tx, userId)
-			if err != nil {
-				return err
-			}
-			faq.StoreID = &storeID
-		default:
-			return ErrUnsupportedRole
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		for _, t := range translations {
-			faq.Translations = append(faq.Translations, models.Translation{
-				Language: t.Language,
-				Question: t.Question,
-				Answer:   t.Answer,
-			})
+	s.indexer.Sync(ctx, createdFAQID)
+
+	return s.loadFAQ(ctx, createdFAQID)
+}
+
+func (s *FAQService) UpdateFAQ(ctx context.Context, id uint, userId uint, categoryId *uint, translations []dtos.TranslationDTO, role types.UserRole) (*models.FAQ, error) {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		faq := models.FAQ{}
+		if err := tx.Preload("Translations").First(&faq, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFAQNotFound
+			}
+			return err
 		}
 
-		if err := tx.Create(&faq).Error; err != nil {
+		if err := s.ensureCanManageFAQ(tx, role, userId, &faq); err != nil {
 			return err
 		}
 
-		createdFAQID = faq.ID
-		return nil
+		return s.updateFAQ(tx, &faq, userId, categoryId, translations, role)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return s.loadFAQ(ctx, createdFAQID)
+	s.indexer.Sync(ctx, id)
+
+	return s.loadFAQ(ctx, id)
 }
 
-func (s *FAQService) UpdateFAQ(ctx context.Context, id uint, userId uint, categoryId *uint, translations []dtos.TranslationDTO, role types.UserRole) (*models.FAQ, error) {
+func (s *FAQService) DeleteFAQ(ctx context.Context, id uint, role types.UserRole, userId uint) error {
 	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		faq := models.FAQ{}
-		if err := tx.Preload("Translations").First(&faq, id).Error; err != nil {
+		if err := tx.First(&faq, id).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return ErrFAQNotFound
 			}
@@ -163,57 +316,56 @@ func (s *FAQService) UpdateFAQ(ctx context.Context, id uint, userId uint, catego
 			return err
 		}
 
-		if categoryId != nil {
-			if err := s.assertCategoryExists(tx, *categoryId); err != nil {
-				return err
-			}
-			faq.CategoryID = *categoryId
-			if err := tx.Model(&faq).Update("category_id", faq.CategoryID).Error; err != nil {
-				return err
-			}
+		if err := recordFAQRevision(tx, faq.ID, userId, types.FAQRevisionDeleted, nil); err != nil {
+			return err
 		}
 
-		existing := make(map[string]models.Translation)
-		for _, tr := range faq.Translations {
-			existing[tr.Language] = tr
-		}
-
-		seen := make(map[string]bool)
-		for _, t := range translations {
-			seen[t.Language] = true
-			if current, ok := existing[t.Language]; ok {
-				if err := tx.Model(&models.Translation{}).
-					Where("id = ?", current.ID).
-					Updates(map[string]interface{}{
-						"question": t.Question,
-						"answer":   t.Answer,
-					}).Error; err != nil {
-					return err
-				}
-			} else {
-				newTranslation := models.Translation{
-					FAQID:    faq.ID,
-					Language: t.Language,
-					Question: t.Question,
-					Answer:   t.Answer,
-				}
-				if err := tx.Create(&newTranslation).Error; err != nil {
-					return err
-				}
+		return softDeleteFAQsWhere(tx, time.Now(), "id = ?", faq.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.indexer.Remove(ctx, id)
+	return nil
+}
+
+// TransitionFAQ moves an FAQ through the draft, in_review, published and
+// archived workflow. Anyone who can manage an FAQ may submit, archive and
+// unarchive it, and merchants approve or reject their own store's FAQs;
+// approving and rejecting global FAQs is reserved for admins.
+func (s *FAQService) TransitionFAQ(ctx context.Context, id uint, userId uint, role types.UserRole, action types.FAQTransition) (*models.FAQ, error) {
+	transition, ok := faqTransitions[action]
+	if !ok {
+		return nil, ErrInvalidFAQAction
+	}
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		faq := models.FAQ{}
+		if err := tx.First(&faq, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFAQNotFound
 			}
+			return err
+		}
+
+		if err := s.ensureCanManageFAQ(tx, role, userId, &faq); err != nil {
+			return err
 		}
 
-		for lang, current := range existing {
-			if !seen[lang] {
-				if err := tx.Delete(&models.Translation{}, current.ID).Error; err != nil {
-					return err
-				}
+		allowed := false
+		for _, from := range transition.from {
+			if faq.Status == from {
+				allowed = true
+				break
 			}
 		}
+		if !allowed {
+			return ErrInvalidFAQAction
+		}
 
-		return nil
+		return tx.Model(&faq).Update("status", transition.to).Error
 	})
-
 	if err != nil {
 		return nil, err
 	}
@@ -221,8 +373,14 @@ func (s *FAQService) UpdateFAQ(ctx context.Context, id uint, userId uint, catego
 	return s.loadFAQ(ctx, id)
 }
 
-func (s *FAQService) DeleteFAQ(ctx context.Context, id uint, role types.UserRole, userId uint) error {
-	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+// ScheduleFAQ replaces the publish window of an FAQ. Scheduling does not
+// change its status: a published FAQ is shown only inside its window.
+func (s *FAQService) ScheduleFAQ(ctx context.Context, id uint, userId uint, role types.UserRole, schedule FAQSchedule) (*models.FAQ, error) {
+	if err := schedule.validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		faq := models.FAQ{}
 		if err := tx.First(&faq, id).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -235,12 +393,16 @@ func (s *FAQService) DeleteFAQ(ctx context.Context, id uint, role types.UserRole
 			return err
 		}
 
-		if err := tx.Where("faq_id = ?", id).Delete(&models.Translation{}).Error; err != nil {
-			return err
-		}
-
-		return tx.Delete(&faq).Error
+		return tx.Model(&faq).Updates(map[string]interface{}{
+			"publish_at": schedule.PublishAt,
+			"expire_at":  schedule.ExpireAt,
+		}).Error
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadFAQ(ctx, id)
 }
 
 func (s *FAQService) AddTranslation(faqId uint, language, question, answer string) (*models.Translation, error) {
@@ -255,6 +417,7 @@ func (s *FAQService) AddTranslation(faqId uint, language, question, answer strin
 	if err != nil {
 		return nil, err
 	}
+	s.indexer.Sync(context.Background(), faqId)
 	return &translation, nil
 }
 
@@ -273,15 +436,195 @@ func (s *FAQService) UpdateTranslation(id uint, language, question, answer strin
 	if err != nil {
 		return nil, err
 	}
+	s.indexer.Sync(context.Background(), translation.FAQID)
 
 	return &translation, nil
 }
 
 func (s *FAQService) DeleteTranslation(id uint) error {
-	err := s.DB.Delete(&models.Translation{}, id).Error
+	var translation models.Translation
+	if err := s.DB.First(&translation, id).Error; err != nil {
+		return err
+	}
+
+	err := s.DB.Delete(&translation).Error
 	if err != nil {
 		return err
 	}
+	s.indexer.Sync(context.Background(), translation.FAQID)
+	return nil
+}
+
+// createFAQ inserts a draft FAQ owned by the caller (the merchant's store, or
+// for admins the given store or else a global FAQ) and records its first
+// revision.
+func (s *FAQService) createFAQ(tx *gorm.DB, userId, categoryId uint, translations []dtos.TranslationDTO, schedule FAQSchedule, role types.UserRole, storeID *uint, externalKey *string) (uint, error) {
+	if err := s.assertCategoryExists(tx, categoryId); err != nil {
+		return 0, err
+	}
+
+	faq := models.FAQ{
+		CategoryID:  categoryId,
+		IsGlobal:    false,
+		Status:      types.FAQStatusDraft,
+		PublishAt:   schedule.PublishAt,
+		ExpireAt:    schedule.ExpireAt,
+		ExternalKey: externalKey,
+	}
+
+	switch role {
+	case types.RoleAdmin:
+		faq.StoreID = storeID
+		faq.IsGlobal = storeID == nil
+	case types.RoleMerchant:
+		if s.RequireVerifiedMerchants {
+			if err := s.assertEmailVerified(tx, userId); err != nil {
+				return 0, err
+			}
+		}
+		storeID, err := s.getMerchantStoreID(tx, userId)
+		if err != nil {
+			return 0, err
+		}
+		faq.StoreID = &storeID
+	default:
+		return 0, ErrUnsupportedRole
+	}
+
+	for _, t := range translations {
+		faq.Translations = append(faq.Translations, models.Translation{
+			Language: t.Language,
+			Question: t.Question,
+			Answer:   t.Answer,
+		})
+	}
+
+	if err := tx.Create(&faq).Error; err != nil {
+		return 0, err
+	}
+
+	return faq.ID, recordFAQRevision(tx, faq.ID, userId, types.FAQRevisionCreated, nil)
+}
+
+// updateFAQ applies a category change and a full translation set to an FAQ
+// the caller may manage, with its translations preloaded.
+func (s *FAQService) updateFAQ(tx *gorm.DB, faq *models.FAQ, userId uint, categoryId *uint, translations []dtos.TranslationDTO, role types.UserRole) error {
+	if categoryId != nil {
+		if err := s.assertCategoryExists(tx, *categoryId); err != nil {
+			return err
+		}
+		if *categoryId != faq.CategoryID {
+			// Positions only order FAQs within one category.
+			if err := tx.Where("faq_id = ?", faq.ID).Delete(&models.FAQPosition{}).Error; err != nil {
+				return err
+			}
+		}
+		faq.CategoryID = *categoryId
+		if err := tx.Model(faq).Update("category_id", faq.CategoryID).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := syncTranslations(tx, faq, translations); err != nil {
+		return err
+	}
+
+	if err := requeueForReview(tx, role, faq); err != nil {
+		return err
+	}
+
+	return recordFAQRevision(tx, faq.ID, userId, types.FAQRevisionUpdated, nil)
+}
+
+// requeueForReview sends a published FAQ edited by a merchant back to review,
+// so an edit only reaches customers once it is approved again. Merchants
+// approve their own store's FAQs, so this is a publishing step rather than
+// admin oversight.
+func requeueForReview(tx *gorm.DB, role types.UserRole, faq *models.FAQ) error {
+	if role == types.RoleAdmin || faq.Status != types.FAQStatusPublished {
+		return nil
+	}
+	faq.Status = types.FAQStatusInReview
+	return tx.Model(faq).Update("status", faq.Status).Error
+}
+
+func (schedule FAQSchedule) validate() error {
+	if schedule.PublishAt != nil && schedule.ExpireAt != nil && !schedule.ExpireAt.After(*schedule.PublishAt) {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
+// liveFAQs limits an FAQ query to published FAQs inside their publish window.
+func liveFAQs(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("faqs.status = ?", types.FAQStatusPublished).
+			Where("faqs.publish_at IS NULL OR faqs.publish_at <= ?", now).
+			Where("faqs.expire_at IS NULL OR faqs.expire_at > ?", now)
+	}
+}
+
+// isLive reports whether an FAQ is published and inside its publish window.
+func isLive(faq *models.FAQ, now time.Time) bool {
+	if faq.Status != types.FAQStatusPublished {
+		return false
+	}
+	if faq.PublishAt != nil && faq.PublishAt.After(now) {
+		return false
+	}
+	return faq.ExpireAt == nil || faq.ExpireAt.After(now)
+}
+
+func isFAQStatus(status types.FAQStatus) bool {
+	switch status {
+	case types.FAQStatusDraft, types.FAQStatusInReview, types.FAQStatusPublished, types.FAQStatusArchived:
+		return true
+	default:
+		return false
+	}
+}
+
+// syncTranslations makes the FAQ's translations match the given set: existing
+// languages are updated, new ones added and missing ones deleted.
+func syncTranslations(tx *gorm.DB, faq *models.FAQ, translations []dtos.TranslationDTO) error {
+	existing := make(map[string]models.Translation)
+	for _, tr := range faq.Translations {
+		existing[tr.Language] = tr
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range translations {
+		seen[t.Language] = true
+		if current, ok := existing[t.Language]; ok {
+			if err := tx.Model(&models.Translation{}).
+				Where("id = ?", current.ID).
+				Updates(map[string]interface{}{
+					"question": t.Question,
+					"answer":   t.Answer,
+				}).Error; err != nil {
+				return err
+			}
+		} else {
+			newTranslation := models.Translation{
+				FAQID:    faq.ID,
+				Language: t.Language,
+				Question: t.Question,
+				Answer:   t.Answer,
+			}
+			if err := tx.Create(&newTranslation).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	for lang, current := range existing {
+		if !seen[lang] {
+			if err := tx.Delete(&models.Translation{}, current.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -289,7 +632,7 @@ func (s *FAQService) loadFAQ(ctx context.Context, id uint) (*models.FAQ, error)
 	faq := models.FAQ{}
 	err := s.DB.WithContext(ctx).
 		Preload("Translations").
-		Preload("Category").
+		Preload("Category.Translations").
 		First(&faq, id).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -309,6 +652,9 @@ func (s *FAQService) ensureCanViewFAQ(ctx context.Context, role types.UserRole,
 		return nil
 	case types.RoleMerchant:
 		if faq.IsGlobal {
+			if !isLive(faq, time.Now()) {
+				return ErrUnauthorizedFAQ
+			}
 			return nil
 		}
 		if faq.StoreID == nil {
@@ -323,7 +669,7 @@ func (s *FAQService) ensureCanViewFAQ(ctx context.Context, role types.UserRole,
 		}
 		return nil
 	case types.RoleCustomer:
-		if faq.IsGlobal {
+		if faq.IsGlobal && isLive(faq, time.Now()) {
 			return nil
 		}
 		return ErrUnauthorizedFAQ
@@ -365,6 +711,21 @@ func (s *FAQService) assertCategoryExists(db *gorm.DB, categoryId uint) error {
 	return nil
 }
 
+func (s *FAQService) assertEmailVerified(db *gorm.DB, userId uint) error {
+	var user models.User
+	err := db.Select("id", "email_verified_at").First(&user, userId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
 func (s *FAQService) getMerchantStoreID(db *gorm.DB, merchantID uint) (uint, error) {
 	var store models.Store
 	err := db.Select("id").Where("merchant_id = ?", merchantID).First(&store).Error
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/logger"
+	"github.com/kareemhamed001/faq/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrTooManyLoginAttempts = errors.New("too many failed login attempts; try again later")
+
+// LoginLockedError reports a temporary lockout and when it ends.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrTooManyLoginAttempts
+}
+
+// LoginThrottleOptions configures lockout thresholds and backoff.
+type LoginThrottleOptions struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	Window             time.Duration
+	BaseLockout        time.Duration
+	MaxLockout         time.Duration
+}
+
+// LoginThrottleService counts failed logins per account and per client IP and
+// locks a key out with exponential backoff once it exceeds its threshold.
+type LoginThrottleService struct {
+	DB      *gorm.DB
+	logger  *logger.Logger
+	options LoginThrottleOptions
+}
+
+func NewLoginThrottleService(DB *gorm.DB, logr *logger.Logger, options LoginThrottleOptions) *LoginThrottleService {
+	return &LoginThrottleService{DB: DB, logger: logr, options: options}
+}
+
+// Check returns a *LoginLockedError if the account or the IP is currently locked out.
+func (s *LoginThrottleService) Check(ctx context.Context, email, clientIP string) error {
+	var throttles []models.LoginThrottle
+	err := s.DB.WithContext(ctx).
+		Where("key IN ? AND locked_until > ?", s.keys(email, clientIP), time.Now()).
+		Find(&throttles).Error
+	if err != nil {
+		return err
+	}
+
+	var retryAfter time.Duration
+	for _, throttle := range throttles {
+		if wait := time.Until(*throttle.LockedUntil); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RegisterFailure records a failed attempt for the account and the IP, locking either out when needed.
+func (s *LoginThrottleService) RegisterFailure(ctx context.Context, email, clientIP string) error {
+	if err := s.registerFailure(ctx, accountThrottleKey(email), s.options.MaxAccountFailures); err != nil {
+		return err
+	}
+	if clientIP == "" {
+		return nil
+	}
+	return s.registerFailure(ctx, ipThrottleKey(clientIP), s.options.MaxIPFailures)
+}
+
+// RegisterSuccess clears the account counter. The IP counter is left alone so
+// one valid login cannot reset a credential-stuffing run from the same address.
+func (s *LoginThrottleService) RegisterSuccess(ctx context.Context, email string) error {
+	return s.DB.WithContext(ctx).
+		Where("key = ?", accountThrottleKey(email)).
+		Delete(&models.LoginThrottle{}).Error
+}
+
+// UnlockUser clears the lockout of a user's account.
+func (s *LoginThrottleService) UnlockUser(ctx context.Context, userID uint) error {
+	var user models.User
+	if err := s.DB.WithContext(ctx).Select("id", "email").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := s.RegisterSuccess(ctx, user.Email); err != nil {
+		return err
+	}
+
+	s.logger.Infow("account unlocked", "user_id", user.ID)
+	return nil
+}
+
+func (s *LoginThrottleService) registerFailure(ctx context.Context, key string, maxFailures int) error {
+	now := time.Now()
+	windowStart := now.Add(-s.options.Window)
+
+	var failures int
+	err := s.DB.WithContext(ctx).Raw(`
+		INSERT INTO login_throttles (key, failures, last_failed_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failed_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING failures`, key, now, windowStart).
+		Scan(&failures).Error
+	if err != nil {
+		return err
+	}
+
+	if maxFailures <= 0 || failures < maxFailures {
+		return nil
+	}
+
+	lockout := s.lockoutFor(failures - maxFailures)
+	lockedUntil := now.Add(lockout)
+	if err := s.DB.WithContext(ctx).
+		Model(&models.LoginThrottle{}).
+		Where("key = ?", key).
+		Update("locked_until", lockedUntil).Error; err != nil {
+		return err
+	}
+
+	s.logger.Warnw("login locked out",
+		"key", key,
+		"failures", failures,
+		"locked_until", lockedUntil,
+	)
+	return nil
+}
+
+// lockoutFor doubles the base lockout for every failure past the threshold, up to the maximum.
+func (s *LoginThrottleService) lockoutFor(excess int) time.Duration {
+	lockout := s.options.BaseLockout
+	for i := 0; i < excess && lockout < s.options.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > s.options.MaxLockout {
+		lockout = s.options.MaxLockout
+	}
+	return lockout
+}
+
+func (s *LoginThrottleService) keys(email, clientIP string) []string {
+	keys := []string{accountThrottleKey(email)}
+	if clientIP != "" {
+		keys = append(keys, ipThrottleKey(clientIP))
+	}
+	return keys
+}
+
+// Keys are derived from the submitted email rather than the user id so unknown
+// addresses are throttled exactly like real ones.
+func accountThrottleKey(email string) string {
+	return fmt.Sprintf("account:%s", strings.ToLower(strings.TrimSpace(email)))
+}
+
+func ipThrottleKey(clientIP string) string {
+	return fmt.Sprintf("ip:%s", clientIP)
+}
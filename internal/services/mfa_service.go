@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMFAToken     = errors.New("invalid or expired mfa token")
+	ErrInvalidMFACode      = errors.New("invalid authentication code")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrMFANotStarted       = errors.New("two-factor enrollment has not been started")
+	ErrMFARequiredByPolicy = errors.New("two-factor authentication is mandatory for this account")
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew accepts codes from one step before and after the current one to absorb clock drift.
+	totpSkew = 1
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPEnrollment holds what a client needs to register the authenticator app.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type MFAService struct {
+	DB          *gorm.DB
+	authService *AuthService
+	settings    *SettingsService
+	issuer      string
+}
+
+func NewMFAService(DB *gorm.DB, authService *AuthService, settings *SettingsService, issuer string) *MFAService {
+	return &MFAService{
+		DB:          DB,
+		authService: authService,
+		settings:    settings,
+		issuer:      issuer,
+	}
+}
+
+// BeginEnrollment generates a new pending TOTP secret. It only becomes active after ConfirmEnrollment.
+func (s *MFAService) BeginEnrollment(ctx context.Context, userID uint) (*TOTPEnrollment, error) {
+	user, err := s.loadUser(s.DB.WithContext(ctx), userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.WithContext(ctx).Model(user).Update("totp_secret", secret).Error; err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    helpers.TOTPAuthURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment activates the pending secret once the user proves possession
+// with a valid code, and returns a fresh set of recovery codes.
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID uint, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		codes, err := s.confirmEnrollment(tx, userID, code)
+		if err != nil {
+			return err
+		}
+		recoveryCodes = codes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Disable turns TOTP off after re-checking the password and a second factor.
+func (s *MFAService) Disable(ctx context.Context, userID uint, password, code string) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := s.loadUser(tx, userID)
+		if err != nil {
+			return err
+		}
+		if user.TOTPEnabledAt == nil {
+			return ErrMFANotEnabled
+		}
+
+		if user.Role == types.RoleAdmin {
+			required, err := s.settings.GetBool(ctx, SettingRequireAdminMFA, false)
+			if err != nil {
+				return err
+			}
+			if required {
+				return ErrMFARequiredByPolicy
+			}
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return ErrIncorrectPassword
+		}
+		if err := s.verifySecondFactor(tx, user, code); err != nil {
+			return err
+		}
+
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"totp_secret":     nil,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", user.ID).Delete(&models.MFARecoveryCode{}).Error
+	})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a second factor.
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := s.loadUser(tx, userID)
+		if err != nil {
+			return err
+		}
+		if user.TOTPEnabledAt == nil {
+			return ErrMFANotEnabled
+		}
+		if err := s.verifySecondFactor(tx, user, code); err != nil {
+			return err
+		}
+
+		codes, err := s.replaceRecoveryCodes(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		recoveryCodes = codes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// CompleteLogin finishes a two-step login with a TOTP or recovery code. Wrong
+// codes count towards the same lockout as wrong passwords.
+func (s *MFAService) CompleteLogin(ctx context.Context, mfaToken, code, clientIP string) (*models.User, *TokenPair, error) {
+	userID, err := s.authService.parseMFAToken(mfaToken, mfaChallengeTokenType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.loadUser(s.DB.WithContext(ctx).Preload("Store"), userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.TOTPEnabledAt == nil {
+		return nil, nil, ErrInvalidMFAToken
+	}
+	if user.SuspendedAt != nil {
+		return nil, nil, ErrAccountSuspended
+	}
+
+	throttle := s.authService.throttle
+	if err := throttle.Check(ctx, user.Email, clientIP); err != nil {
+		return nil, nil, err
+	}
+
+	var tokens *TokenPair
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.verifySecondFactor(tx, user, code); err != nil {
+			return err
+		}
+
+		pair, err := s.authService.startSession(tx, user)
+		if err != nil {
+			return err
+		}
+		tokens = pair
+		return nil
+	})
+	if errors.Is(err, ErrInvalidMFACode) {
+		if err := throttle.RegisterFailure(ctx, user.Email, clientIP); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := throttle.RegisterSuccess(ctx, user.Email); err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// BeginLoginEnrollment starts the mandatory enrollment of a user who logged in
+// while policy requires TOTP but has none configured yet.
+func (s *MFAService) BeginLoginEnrollment(ctx context.Context, mfaToken string) (*TOTPEnrollment, error) {
+	userID, err := s.authService.parseMFAToken(mfaToken, mfaEnrollmentTokenType)
+	if err != nil {
+		return nil, err
+	}
+	return s.BeginEnrollment(ctx, userID)
+}
+
+// CompleteLoginEnrollment confirms the mandatory enrollment and opens the session.
+func (s *MFAService) CompleteLoginEnrollment(ctx context.Context, mfaToken, code string) (*models.User, *TokenPair, []string, error) {
+	userID, err := s.authService.parseMFAToken(mfaToken, mfaEnrollmentTokenType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		user          *models.User
+		tokens        *TokenPair
+		recoveryCodes []string
+	)
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		codes, err := s.confirmEnrollment(tx, userID, code)
+		if err != nil {
+			return err
+		}
+
+		loaded, err := s.loadUser(tx.Preload("Store"), userID)
+		if err != nil {
+			return err
+		}
+
+		pair, err := s.authService.startSession(tx, loaded)
+		if err != nil {
+			return err
+		}
+		user, tokens, recoveryCodes = loaded, pair, codes
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return user, tokens, recoveryCodes, nil
+}
+
+func (s *MFAService) confirmEnrollment(tx *gorm.DB, userID uint, code string) ([]string, error) {
+	user, err := s.loadUser(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotStarted
+	}
+
+	step, ok := helpers.ValidateTOTP(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	if err := tx.Model(user).Updates(map[string]interface{}{
+		"totp_enabled_at": time.Now(),
+		"totp_last_step":  step,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(tx, user.ID)
+}
+
+// verifySecondFactor accepts a TOTP code (each time step at most once) or an unused recovery code.
+func (s *MFAService) verifySecondFactor(tx *gorm.DB, user *models.User, code string) error {
+	if step, ok := helpers.ValidateTOTP(user.TOTPSecret, code, time.Now(), totpSkew); ok {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND totp_last_step < ?", user.ID, step).
+			Update("totp_last_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	result := tx.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, helpers.HashToken(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *MFAService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.MFARecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, models.MFARecoveryCode{
+			UserID:   userID,
+			CodeHash: helpers.HashToken(normalizeRecoveryCode(code)),
+		})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *MFAService) loadUser(db *gorm.DB, userID uint) (*models.User, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// generateRecoveryCode returns a code like "abcde-fghij".
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))[:10]
+	return raw[:5] + "-" + raw[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
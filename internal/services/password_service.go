@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/helpers"
+	"github.com/kareemhamed001/faq/internal/logger"
+	"github.com/kareemhamed001/faq/internal/mailer"
+	"github.com/kareemhamed001/faq/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrPasswordUnchanged = errors.New("new password must differ from the current password")
+	ErrUserNotFound      = errors.New("user not found")
+)
+
+// resetMailTimeout bounds sending a reset email, which outlives the request.
+const resetMailTimeout = 30 * time.Second
+
+type PasswordService struct {
+	DB            *gorm.DB
+	authService   *AuthService
+	mailer        mailer.Mailer
+	logger        *logger.Logger
+	resetURL      string
+	resetTokenTTL time.Duration
+}
+
+func NewPasswordService(DB *gorm.DB, authService *AuthService, m mailer.Mailer, logr *logger.Logger, resetURL string, resetTokenTTL time.Duration) *PasswordService {
+	return &PasswordService{
+		DB:            DB,
+		authService:   authService,
+		mailer:        m,
+		logger:        logr,
+		resetURL:      resetURL,
+		resetTokenTTL: resetTokenTTL,
+	}
+}
+
+// ForgotPassword emails a reset link to the given address. Unknown emails are
+// silently ignored so the endpoint cannot be used to discover accounts. For the
+// same reason the email is sent in the background and a failure to send it is
+// only logged: neither the response nor its timing depends on the mailer.
+func (s *PasswordService) ForgotPassword(ctx context.Context, email string) error {
+	var user models.User
+	err := s.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rawToken, err := helpers.GenerateOpaqueToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only the most recent link stays usable.
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: helpers.HashToken(rawToken),
+			ExpiresAt: time.Now().Add(s.resetTokenTTL),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password. It expires in %s.\n\n%s?token=%s\n\nIf you did not request this, you can ignore this email.\n",
+			user.Name, s.resetTokenTTL, s.resetURL, rawToken),
+	}
+	go func() {
+		sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resetMailTimeout)
+		defer cancel()
+		if err := s.mailer.Send(sendCtx, msg); err != nil {
+			s.logger.Errorw("failed to send password reset email", "user_id", user.ID, "error", err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes every session of the user.
+func (s *PasswordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reset models.PasswordResetToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", helpers.HashToken(token)).
+			First(&reset).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+			return ErrInvalidResetToken
+		}
+
+		if err := s.setPassword(tx, reset.UserID, newPassword); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&reset).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return s.authService.revokeUserSessions(tx, reset.UserID, "")
+	})
+}
+
+// ChangePassword updates the password of an authenticated user after checking
+// the current one. Every session except currentSessionID is revoked.
+func (s *PasswordService) ChangePassword(ctx context.Context, userID uint, currentSessionID, currentPassword, newPassword string) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+			return ErrIncorrectPassword
+		}
+		if currentPassword == newPassword {
+			return ErrPasswordUnchanged
+		}
+
+		if err := s.setPassword(tx, user.ID, newPassword); err != nil {
+			return err
+		}
+
+		return s.authService.revokeUserSessions(tx, user.ID, currentSessionID)
+	})
+}
+
+func (s *PasswordService) setPassword(db *gorm.DB, userID uint, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("Error while hashing password")
+	}
+	return db.Model(&models.User{}).Where("id = ?", userID).Update("password", string(hashedPassword)).Error
+}
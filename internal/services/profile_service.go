@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailTaken    = errors.New("email already registered")
+	ErrLastAdmin     = errors.New("the last active admin account cannot be closed")
+	ErrNothingToSave = errors.New("no changes provided")
+)
+
+// ProfileUpdate holds the fields a user may change on their own account; nil means unchanged.
+type ProfileUpdate struct {
+	Name  *string
+	Email *string
+}
+
+type ProfileService struct {
+	DB          *gorm.DB
+	authService *AuthService
+}
+
+func NewProfileService(DB *gorm.DB, authService *AuthService) *ProfileService {
+	return &ProfileService{
+		DB:          DB,
+		authService: authService,
+	}
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, userID uint) (*models.User, error) {
+	var user models.User
+	if err := s.DB.WithContext(ctx).Preload("Store").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateProfile changes name and/or email and reports whether the email
+// changed. A new email is unverified until a verification link sent to it is
+// confirmed; sending it is up to the caller.
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID uint, update ProfileUpdate) (*models.User, bool, error) {
+	if update.Name == nil && update.Email == nil {
+		return nil, false, ErrNothingToSave
+	}
+
+	emailChanged := false
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		changes := map[string]interface{}{}
+		if update.Name != nil {
+			changes["name"] = strings.TrimSpace(*update.Name)
+		}
+
+		if update.Email != nil && !strings.EqualFold(*update.Email, user.Email) {
+			// Soft-deleted accounts still hold their address in the unique index.
+			var taken int64
+			if err := tx.Unscoped().Model(&models.User{}).
+				Where("LOWER(email) = LOWER(?) AND id <> ?", *update.Email, user.ID).
+				Count(&taken).Error; err != nil {
+				return err
+			}
+			if taken > 0 {
+				return ErrEmailTaken
+			}
+
+			changes["email"] = *update.Email
+			changes["email_verified_at"] = nil
+			changes["verification_sent_at"] = nil
+			emailChanged = true
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Model(&user).Updates(changes).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	user, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user, emailChanged, nil
+}
+
+// CloseAccount soft-deletes the caller's account after confirming the password,
+// revoking all sessions and API keys. A merchant's store disappears with it.
+func (s *ProfileService) CloseAccount(ctx context.Context, userID uint, password string) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return ErrIncorrectPassword
+		}
+
+		if user.Role == types.RoleAdmin {
+			var otherAdmins int64
+			if err := tx.Model(&models.User{}).
+				Where("role = ? AND id <> ? AND suspended_at IS NULL", types.RoleAdmin, user.ID).
+				Count(&otherAdmins).Error; err != nil {
+				return err
+			}
+			if otherAdmins == 0 {
+				return ErrLastAdmin
+			}
+		}
+
+		if err := s.authService.revokeUserAccess(tx, user.ID); err != nil {
+			return err
+		}
+
+		return softDeleteUser(tx, user.ID)
+	})
+}
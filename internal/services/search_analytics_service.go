@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSearchEventNotFound = errors.New("search not found")
+	ErrInvalidDateRange    = errors.New("from must be before to")
+	ErrInvalidSearchSource = errors.New("source must be one of faqs, store, ask or suggest")
+)
+
+const (
+	defaultReportRange = 30 * 24 * time.Hour
+	maxReportQueries   = 100
+)
+
+// customerSearchSources are the sources a report's query lists cover unless
+// sources are chosen: the searches customers submit. Autocomplete records a
+// search per keystroke and the FAQ list records back-office searches, which
+// would crowd out what customers actually look for.
+var customerSearchSources = []types.SearchSource{types.SearchSourceStore, types.SearchSourceAsk}
+
+// SearchReportFilter selects the searches a report covers. A zero From or To
+// defaults to the last 30 days.
+type SearchReportFilter struct {
+	From    time.Time
+	To      time.Time
+	StoreID *uint                // admins only; merchants always see their own stores
+	Sources []types.SearchSource // all when empty, with query lists limited to customerSearchSources
+	Limit   int                  // rows per query list, at most 100
+}
+
+// SearchStats counts searches and clicks. ClickThroughRate is the share of
+// searches after which a result was opened.
+type SearchStats struct {
+	Searches         int64   `json:"searches"`
+	ZeroResults      int64   `json:"zero_results"`
+	Clicks           int64   `json:"clicks"`
+	ClickThroughRate float64 `json:"click_through_rate"`
+}
+
+type QuerySearchStats struct {
+	Query string `json:"query"`
+	SearchStats
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+type LanguageSearchStats struct {
+	Language string `json:"language"`
+	SearchStats
+}
+
+type SourceSearchStats struct {
+	Source types.SearchSource `json:"source"`
+	SearchStats
+}
+
+type SearchReport struct {
+	From              time.Time             `json:"from"`
+	To                time.Time             `json:"to"`
+	Totals            SearchStats           `json:"totals"`
+	QuerySources      []types.SearchSource  `json:"query_sources"` // sources the query lists cover
+	TopQueries        []QuerySearchStats    `json:"top_queries"`
+	ZeroResultQueries []QuerySearchStats    `json:"zero_result_queries"`
+	Languages         []LanguageSearchStats `json:"languages"`
+	Sources           []SourceSearchStats   `json:"sources"`
+}
+
+// SearchAnalyticsService records what customers and users search for and
+// reports on it.
+type SearchAnalyticsService struct {
+	DB *gorm.DB
+}
+
+func NewSearchAnalyticsService(DB *gorm.DB) *SearchAnalyticsService {
+	return &SearchAnalyticsService{DB: DB}
+}
+
+// RecordSearch stores one search. Queries are grouped in reports by their
+// lower-cased, whitespace-collapsed form.
+func (s *SearchAnalyticsService) RecordSearch(ctx context.Context, source types.SearchSource, storeID *uint, query, language string, resultCount int) (*models.SearchEvent, error) {
+	event := models.SearchEvent{
+		Source:          source,
+		StoreID:         storeID,
+		Query:           strings.TrimSpace(query),
+		NormalizedQuery: normalizeSearchQuery(query),
+		Language:        language,
+		ResultCount:     resultCount,
+	}
+	if err := s.DB.WithContext(ctx).Create(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// RecordClick marks the FAQ opened from a search's results. Only the first
+// click of a search counts, and only on an FAQ visible where the search was
+// made; any other FAQ is reported as not found.
+func (s *SearchAnalyticsService) RecordClick(ctx context.Context, searchID, faqID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var event models.SearchEvent
+		if err := tx.First(&event, searchID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSearchEventNotFound
+			}
+			return err
+		}
+		if event.ClickedAt != nil {
+			return nil
+		}
+
+		// The FAQ must be one the search could have returned: from the store
+		// searched or global, and live unless it came from the FAQ management
+		// list, which also shows drafts.
+		query := tx.Model(&models.FAQ{}).Where("id = ?", faqID)
+		if event.StoreID != nil {
+			query = query.Where("store_id = ? OR is_global = ?", *event.StoreID, true)
+		}
+		if event.Source != types.SearchSourceFAQs {
+			query = query.Scopes(liveFAQs(time.Now()))
+		}
+		var faqs int64
+		if err := query.Count(&faqs).Error; err != nil {
+			return err
+		}
+		if faqs == 0 {
+			return ErrFAQNotFound
+		}
+
+		return tx.Model(&models.SearchEvent{}).
+			Where("id = ? AND clicked_at IS NULL", searchID).
+			Updates(map[string]interface{}{"clicked_faq_id": faqID, "clicked_at": time.Now()}).Error
+	})
+}
+
+// GetSearchReport summarises the searches in a date range: the most frequent
+// queries, the queries that found nothing, click-through rates and
+// per-language and per-source breakdowns. Merchants only see searches made in
+// their stores.
+func (s *SearchAnalyticsService) GetSearchReport(ctx context.Context, role types.UserRole, userId uint, filter SearchReportFilter) (*SearchReport, error) {
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := filter.From
+	if from.IsZero() {
+		from = to.Add(-defaultReportRange)
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidDateRange
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > maxReportQueries {
+		limit = 20
+	}
+
+	querySources := customerSearchSources
+	if len(filter.Sources) > 0 {
+		for _, source := range filter.Sources {
+			if !isSearchSource(source) {
+				return nil, ErrInvalidSearchSource
+			}
+		}
+		querySources = filter.Sources
+	}
+
+	query := s.DB.WithContext(ctx).
+		Model(&models.SearchEvent{}).
+		Where("created_at >= ? AND created_at < ?", from, to)
+
+	switch role {
+	case types.RoleAdmin:
+		if filter.StoreID != nil {
+			query = query.Where("store_id = ?", *filter.StoreID)
+		}
+	case types.RoleMerchant:
+		query = query.Where("store_id IN (SELECT id FROM stores WHERE merchant_id = ?)", userId)
+	default:
+		return nil, ErrUnsupportedRole
+	}
+	if len(filter.Sources) > 0 {
+		query = query.Where("source IN ?", filter.Sources)
+	}
+	query = query.Session(&gorm.Session{})
+
+	const stats = "COUNT(*) AS searches, COUNT(*) FILTER (WHERE result_count = 0) AS zero_results, COUNT(clicked_at) AS clicks"
+
+	report := SearchReport{
+		From:              from,
+		To:                to,
+		QuerySources:      querySources,
+		TopQueries:        []QuerySearchStats{},
+		ZeroResultQueries: []QuerySearchStats{},
+		Languages:         []LanguageSearchStats{},
+		Sources:           []SourceSearchStats{},
+	}
+
+	if err := query.Select(stats).Scan(&report.Totals).Error; err != nil {
+		return nil, err
+	}
+
+	err := query.Select("normalized_query AS query, MAX(created_at) AS last_searched_at, "+stats).
+		Where("source IN ?", querySources).
+		Group("normalized_query").
+		Order("searches DESC, query").
+		Limit(limit).
+		Scan(&report.TopQueries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.Select("normalized_query AS query, MAX(created_at) AS last_searched_at, "+stats).
+		Where("source IN ?", querySources).
+		Where("result_count = 0").
+		Group("normalized_query").
+		Order("searches DESC, query").
+		Limit(limit).
+		Scan(&report.ZeroResultQueries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.Select("language, " + stats).
+		Group("language").
+		Order("searches DESC, language").
+		Scan(&report.Languages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.Select("source, " + stats).
+		Group("source").
+		Order("searches DESC, source").
+		Scan(&report.Sources).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report.Totals.setClickThroughRate()
+	for i := range report.TopQueries {
+		report.TopQueries[i].setClickThroughRate()
+	}
+	for i := range report.ZeroResultQueries {
+		report.ZeroResultQueries[i].setClickThroughRate()
+	}
+	for i := range report.Languages {
+		report.Languages[i].setClickThroughRate()
+	}
+	for i := range report.Sources {
+		report.Sources[i].setClickThroughRate()
+	}
+
+	return &report, nil
+}
+
+func (s *SearchStats) setClickThroughRate() {
+	if s.Searches > 0 {
+		s.ClickThroughRate = float64(s.Clicks) / float64(s.Searches)
+	}
+}
+
+func isSearchSource(source types.SearchSource) bool {
+	switch source {
+	case types.SearchSourceFAQs, types.SearchSourceStore, types.SearchSourceAsk, types.SearchSourceSuggest:
+		return true
+	default:
+		return false
+	}
+}
+
+func normalizeSearchQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
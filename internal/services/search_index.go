@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/search"
+	"gorm.io/gorm"
+)
+
+// SearchIndex finds FAQs by free text. Implementations may hold FAQs that
+// were deleted together with their owner; callers re-check every hit against
+// the database, so such entries never surface.
+type SearchIndex interface {
+	// Index replaces the documents of an FAQ, one per translation.
+	Index(ctx context.Context, faqID uint, docs []search.Document) error
+	Delete(ctx context.Context, faqIDs ...uint) error
+	// Reset removes every document ahead of a rebuild.
+	Reset(ctx context.Context) error
+	Search(ctx context.Context, q search.Query) ([]search.Hit, error)
+}
+
+var ErrUnsupportedSearchBackend = errors.New("unsupported search backend")
+
+// OpenSearchIndex returns the search index for a backend: "postgres" searches
+// the database, "local" keeps a BM25 index in process and saves it at localPath.
+func OpenSearchIndex(backend string, DB *gorm.DB, localPath string) (SearchIndex, error) {
+	switch backend {
+	case "postgres":
+		return NewPostgresSearchIndex(DB), nil
+	case "local":
+		return search.NewLocalIndex(localPath)
+	default:
+		return nil, ErrUnsupportedSearchBackend
+	}
+}
+
+// PostgresSearchIndex searches the translations table directly through its
+// generated full-text column and trigram index, so there is nothing to sync.
+type PostgresSearchIndex struct {
+	DB *gorm.DB
+}
+
+func NewPostgresSearchIndex(DB *gorm.DB) *PostgresSearchIndex {
+	return &PostgresSearchIndex{DB: DB}
+}
+
+func (p *PostgresSearchIndex) Index(context.Context, uint, []search.Document) error { return nil }
+
+func (p *PostgresSearchIndex) Delete(context.Context, ...uint) error { return nil }
+
+func (p *PostgresSearchIndex) Reset(context.Context) error { return nil }
+
+// Search ranks full-text matches highest; questions that only resemble the
+// text (typos, partial words) still match through trigram similarity.
+func (p *PostgresSearchIndex) Search(ctx context.Context, q search.Query) ([]search.Hit, error) {
+	text := strings.TrimSpace(q.Text)
+	hits := []search.Hit{}
+	if text == "" {
+		return hits, nil
+	}
+
+	limit := q.Limit
+	if limit <= 0 || limit > search.MaxHits {
+		limit = search.MaxHits
+	}
+
+	query := p.DB.WithContext(ctx).
+		Table("translations").
+		Select("translations.faq_id, MAX(ts_rank(translations.search_vector, faq_tsquery(?)) + word_similarity(?, translations.question)) AS score", text, text).
+		Joins("JOIN faqs ON faqs.id = translations.faq_id").
+		Where("translations.deleted_at IS NULL").
+		Where("translations.search_vector @@ faq_tsquery(?) OR ? <% translations.question", text, text)
+
+	if len(q.Filter.StoreIDs) > 0 {
+		if q.Filter.IncludeGlobal {
+			query = query.Where("faqs.store_id IN ? OR faqs.store_id IS NULL", q.Filter.StoreIDs)
+		} else {
+			query = query.Where("faqs.store_id IN ?", q.Filter.StoreIDs)
+		}
+	}
+	if len(q.Filter.CategoryIDs) > 0 {
+		query = query.Where("faqs.category_id IN ?", q.Filter.CategoryIDs)
+	}
+	if q.Filter.Language != "" {
+		query = query.Where("translations.language = ?", q.Filter.Language)
+	}
+
+	err := query.Group("translations.faq_id").
+		Order("score DESC, translations.faq_id").
+		Limit(limit).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// FAQIndexer keeps a SearchIndex in step with the FAQs in the database. A nil
+// indexer does nothing, for tools that never search.
+type FAQIndexer struct {
+	DB      *gorm.DB
+	index   SearchIndex
+	onError func(error)
+}
+
+const rebuildBatchSize = 500
+
+// NewFAQIndexer returns an indexer for index. Sync and Remove run after the
+// change they follow is committed, so they report failures to onError (which
+// may be nil) rather than to the caller.
+func NewFAQIndexer(DB *gorm.DB, index SearchIndex, onError func(error)) *FAQIndexer {
+	return &FAQIndexer{DB: DB, index: index, onError: onError}
+}
+
+func (x *FAQIndexer) Search(ctx context.Context, q search.Query) ([]search.Hit, error) {
+	if x == nil {
+		return []search.Hit{}, nil
+	}
+	return x.index.Search(ctx, q)
+}
+
+// matcher is implemented by indexes that report which translation matched and
+// how confident the match is, such as the local BM25 index.
+type matcher interface {
+	Match(q search.Query) []search.Match
+}
+
+// Match ranks like Search and also reports the best translation of each FAQ
+// and the confidence of the match. For indexes that do not measure confidence
+// it is the unweighted share of the query's terms the translation contains.
+func (x *FAQIndexer) Match(ctx context.Context, q search.Query) ([]search.Match, error) {
+	if x == nil {
+		return []search.Match{}, nil
+	}
+	if m, ok := x.index.(matcher); ok {
+		return m.Match(q), nil
+	}
+
+	hits, err := x.index.Search(ctx, q)
+	if err != nil || len(hits) == 0 {
+		return []search.Match{}, err
+	}
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.FAQID
+	}
+
+	query := x.DB.WithContext(ctx).Where("faq_id IN ?", ids)
+	if q.Filter.Language != "" {
+		query = query.Where("language = ?", q.Filter.Language)
+	}
+	var translations []models.Translation
+	if err := query.Find(&translations).Error; err != nil {
+		return nil, err
+	}
+
+	language := q.Language
+	if language == "" {
+		language = q.Filter.Language
+	}
+	terms := search.Analyze(language, q.Text)
+
+	best := make(map[uint]search.Match, len(hits))
+	for _, t := range translations {
+		confidence := search.Coverage(terms, t.Question+" "+t.Answer)
+		if current, ok := best[t.FAQID]; ok && current.Confidence >= confidence {
+			continue
+		}
+		best[t.FAQID] = search.Match{FAQID: t.FAQID, Language: t.Language, Confidence: confidence}
+	}
+
+	matches := make([]search.Match, 0, len(hits))
+	for _, hit := range hits {
+		if m, ok := best[hit.FAQID]; ok {
+			m.Score = hit.Score
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+// Sync re-reads the given FAQs, in batches, and indexes their live
+// translations. FAQs that no longer exist, or are in the trash, are removed
+// from the index. Indexes that read the database directly are left alone. A
+// local index that misses an update is reconciled on the next start.
+func (x *FAQIndexer) Sync(ctx context.Context, faqIDs ...uint) {
+	if x == nil || len(faqIDs) == 0 {
+		return
+	}
+	if _, ok := x.index.(*PostgresSearchIndex); ok {
+		return
+	}
+
+	for start := 0; start < len(faqIDs); start += rebuildBatchSize {
+		end := min(start+rebuildBatchSize, len(faqIDs))
+		if err := x.syncBatch(ctx, faqIDs[start:end]); err != nil {
+			x.report(err)
+		}
+	}
+}
+
+func (x *FAQIndexer) syncBatch(ctx context.Context, faqIDs []uint) error {
+	var faqs []models.FAQ
+	if err := x.DB.WithContext(ctx).Preload("Translations").Where("id IN ?", faqIDs).Find(&faqs).Error; err != nil {
+		return err
+	}
+
+	found := make(map[uint]bool, len(faqs))
+	for i := range faqs {
+		found[faqs[i].ID] = true
+		if err := x.index.Index(ctx, faqs[i].ID, faqDocuments(&faqs[i])); err != nil {
+			return err
+		}
+	}
+
+	var gone []uint
+	for _, id := range faqIDs {
+		if !found[id] {
+			gone = append(gone, id)
+		}
+	}
+	if len(gone) == 0 {
+		return nil
+	}
+	return x.index.Delete(ctx, gone...)
+}
+
+func (x *FAQIndexer) Remove(ctx context.Context, faqIDs ...uint) {
+	if x == nil || len(faqIDs) == 0 {
+		return
+	}
+	if err := x.index.Delete(ctx, faqIDs...); err != nil {
+		x.report(err)
+	}
+}
+
+func (x *FAQIndexer) report(err error) {
+	if x.onError != nil {
+		x.onError(err)
+	}
+}
+
+// Rebuild empties the index and indexes every live FAQ again, returning how
+// many FAQs were indexed.
+func (x *FAQIndexer) Rebuild(ctx context.Context) (int, error) {
+	if x == nil {
+		return 0, nil
+	}
+	if err := x.index.Reset(ctx); err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	var lastID uint
+	for {
+		var faqs []models.FAQ
+		err := x.DB.WithContext(ctx).
+			Preload("Translations").
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(rebuildBatchSize).
+			Find(&faqs).Error
+		if err != nil {
+			return indexed, err
+		}
+
+		for i := range faqs {
+			if err := x.index.Index(ctx, faqs[i].ID, faqDocuments(&faqs[i])); err != nil {
+				return indexed, err
+			}
+		}
+		indexed += len(faqs)
+
+		if len(faqs) < rebuildBatchSize {
+			return indexed, nil
+		}
+		lastID = faqs[len(faqs)-1].ID
+	}
+}
+
+// documentLister is implemented by indexes that can list what they hold, so
+// they can be reconciled with the database instead of rebuilt.
+type documentLister interface {
+	FAQIDs() []uint
+	Documents(faqID uint) []search.Document
+}
+
+// Reconcile brings an index that was loaded from disk up to date: FAQs whose
+// documents differ from the database are indexed again and FAQs that are gone
+// or in the trash are removed. It returns how many FAQs it changed. Indexes
+// that cannot list their documents are left alone.
+func (x *FAQIndexer) Reconcile(ctx context.Context) (int, error) {
+	if x == nil {
+		return 0, nil
+	}
+	lister, ok := x.index.(documentLister)
+	if !ok {
+		return 0, nil
+	}
+
+	stale := make(map[uint]bool)
+	for _, id := range lister.FAQIDs() {
+		stale[id] = true
+	}
+
+	changed := 0
+	var lastID uint
+	for {
+		var faqs []models.FAQ
+		err := x.DB.WithContext(ctx).
+			Preload("Translations").
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(rebuildBatchSize).
+			Find(&faqs).Error
+		if err != nil {
+			return changed, err
+		}
+
+		for i := range faqs {
+			delete(stale, faqs[i].ID)
+			docs := faqDocuments(&faqs[i])
+			if sameDocuments(docs, lister.Documents(faqs[i].ID)) {
+				continue
+			}
+			if err := x.index.Index(ctx, faqs[i].ID, docs); err != nil {
+				return changed, err
+			}
+			changed++
+		}
+
+		if len(faqs) < rebuildBatchSize {
+			break
+		}
+		lastID = faqs[len(faqs)-1].ID
+	}
+
+	if len(stale) == 0 {
+		return changed, nil
+	}
+	gone := make([]uint, 0, len(stale))
+	for id := range stale {
+		gone = append(gone, id)
+	}
+	return changed + len(gone), x.index.Delete(ctx, gone...)
+}
+
+// sameDocuments reports whether two document sets of an FAQ hold the same
+// translations, in any order.
+func sameDocuments(a, b []search.Document) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byLanguage := make(map[string]search.Document, len(a))
+	for _, doc := range a {
+		byLanguage[doc.Language] = doc
+	}
+	for _, doc := range b {
+		other, ok := byLanguage[doc.Language]
+		if !ok || other.FAQID != doc.FAQID || other.CategoryID != doc.CategoryID ||
+			other.Question != doc.Question || other.Answer != doc.Answer {
+			return false
+		}
+		if (other.StoreID == nil) != (doc.StoreID == nil) || (other.StoreID != nil && *other.StoreID != *doc.StoreID) {
+			return false
+		}
+	}
+	return true
+}
+
+func faqDocuments(faq *models.FAQ) []search.Document {
+	docs := make([]search.Document, 0, len(faq.Translations))
+	for _, t := range faq.Translations {
+		docs = append(docs, search.Document{
+			FAQID:      faq.ID,
+			StoreID:    faq.StoreID,
+			CategoryID: faq.CategoryID,
+			Language:   t.Language,
+			Question:   t.Question,
+			Answer:     t.Answer,
+		})
+	}
+	return docs
+}
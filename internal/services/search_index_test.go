@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kareemhamed001/faq/internal/search"
+)
+
+func TestSameDocuments(t *testing.T) {
+	store := uint(3)
+	otherStore := uint(4)
+	en := search.Document{FAQID: 1, StoreID: &store, CategoryID: 2, Language: "en", Question: "Q", Answer: "A"}
+	ar := search.Document{FAQID: 1, StoreID: &store, CategoryID: 2, Language: "ar", Question: "س", Answer: "ج"}
+
+	changed := func(edit func(d *search.Document)) search.Document {
+		d := en
+		edit(&d)
+		return d
+	}
+
+	tests := []struct {
+		name string
+		a, b []search.Document
+		same bool
+	}{
+		{"identical", []search.Document{en, ar}, []search.Document{en, ar}, true},
+		{"different order", []search.Document{en, ar}, []search.Document{ar, en}, true},
+		{"both empty", nil, []search.Document{}, true},
+		{"missing translation", []search.Document{en, ar}, []search.Document{en}, false},
+		{"edited answer", []search.Document{en}, []search.Document{changed(func(d *search.Document) { d.Answer = "B" })}, false},
+		{"moved category", []search.Document{en}, []search.Document{changed(func(d *search.Document) { d.CategoryID = 5 })}, false},
+		{"moved store", []search.Document{en}, []search.Document{changed(func(d *search.Document) { d.StoreID = &otherStore })}, false},
+		{"made global", []search.Document{en}, []search.Document{changed(func(d *search.Document) { d.StoreID = nil })}, false},
+	}
+	for _, tt := range tests {
+		if got := sameDocuments(tt.a, tt.b); got != tt.same {
+			t.Errorf("%s: sameDocuments = %v, want %v", tt.name, got, tt.same)
+		}
+	}
+}
+
+func TestSyncSkipsDatabaseBackedIndex(t *testing.T) {
+	// The postgres index reads the database itself, so Sync must not reload
+	// anything; a nil DB would panic if it tried.
+	var reported []error
+	x := NewFAQIndexer(nil, NewPostgresSearchIndex(nil), func(err error) { reported = append(reported, err) })
+	x.Sync(context.Background(), 1, 2, 3)
+	x.Remove(context.Background(), 1)
+	if len(reported) != 0 {
+		t.Errorf("reported %v", reported)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Setting keys.
+const (
+	SettingRequireAdminMFA       = "security.require_admin_mfa"
+	SettingFAQSchedulerCheckedAt = "scheduler.faq_checked_at"
+)
+
+// SecuritySettings is the admin-editable security policy.
+type SecuritySettings struct {
+	RequireAdminMFA bool `json:"require_admin_mfa"`
+}
+
+type SettingsService struct {
+	DB *gorm.DB
+}
+
+func NewSettingsService(DB *gorm.DB) *SettingsService {
+	return &SettingsService{DB: DB}
+}
+
+func (s *SettingsService) GetSecuritySettings(ctx context.Context) (*SecuritySettings, error) {
+	requireAdminMFA, err := s.GetBool(ctx, SettingRequireAdminMFA, false)
+	if err != nil {
+		return nil, err
+	}
+	return &SecuritySettings{RequireAdminMFA: requireAdminMFA}, nil
+}
+
+func (s *SettingsService) UpdateSecuritySettings(ctx context.Context, settings SecuritySettings) (*SecuritySettings, error) {
+	if err := s.SetBool(ctx, SettingRequireAdminMFA, settings.RequireAdminMFA); err != nil {
+		return nil, err
+	}
+	return s.GetSecuritySettings(ctx)
+}
+
+// GetBool reads a boolean setting, returning fallback when it is unset or malformed.
+func (s *SettingsService) GetBool(ctx context.Context, key string, fallback bool) (bool, error) {
+	raw, ok, err := s.getValue(ctx, key)
+	if err != nil || !ok {
+		return fallback, err
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return fallback, nil
+	}
+	return value, nil
+}
+
+func (s *SettingsService) SetBool(ctx context.Context, key string, value bool) error {
+	return s.setValue(ctx, key, strconv.FormatBool(value))
+}
+
+// GetTime reads a timestamp setting, returning the zero time when it is unset or malformed.
+func (s *SettingsService) GetTime(ctx context.Context, key string) (time.Time, error) {
+	raw, ok, err := s.getValue(ctx, key)
+	if err != nil || !ok {
+		return time.Time{}, err
+	}
+
+	value, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return value, nil
+}
+
+func (s *SettingsService) SetTime(ctx context.Context, key string, value time.Time) error {
+	return s.setValue(ctx, key, value.Format(time.RFC3339Nano))
+}
+
+func (s *SettingsService) getValue(ctx context.Context, key string) (string, bool, error) {
+	var setting models.Setting
+	err := s.DB.WithContext(ctx).Where("key = ?", key).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return setting.Value, true, nil
+}
+
+func (s *SettingsService) setValue(ctx context.Context, key, value string) error {
+	return s.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).
+		Create(&models.Setting{Key: key, Value: value}).Error
+}
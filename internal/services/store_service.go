@@ -2,18 +2,44 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/search"
+	"github.com/kareemhamed001/faq/internal/types"
 	"gorm.io/gorm"
 )
 
+var ErrSearchQueryRequired = errors.New("search query is required")
+
+// FAQSuggestion is an FAQ question that resembles what a customer is typing.
+type FAQSuggestion struct {
+	FAQID      uint    `json:"faq_id"`
+	Language   string  `json:"language"`
+	Question   string  `json:"question"`
+	Similarity float64 `json:"similarity"`
+}
+
+const (
+	// suggestThreshold is the minimum word similarity for a suggestion. It is
+	// lower than pg_trgm's default because autocomplete sees partial words.
+	suggestThreshold = 0.3
+	maxSuggestions   = 20
+	maxTopFAQs       = 50
+)
+
 type StoreService struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	indexer *FAQIndexer
+	views   *ViewCounter
 }
 
-func NewStoreService(DB *gorm.DB) *StoreService {
-	return &StoreService{DB: DB}
+func NewStoreService(DB *gorm.DB, indexer *FAQIndexer, views *ViewCounter) *StoreService {
+	return &StoreService{DB: DB, indexer: indexer, views: views}
 }
 
 func (s *StoreService) ListStores(ctx context.Context, page, pageSize int, sortDir string) ([]models.Store, error) {
@@ -24,14 +50,21 @@ func (s *StoreService) ListStores(ctx context.Context, page, pageSize int, sortD
 		pageSize = 20
 	}
 
+	query := s.DB.WithContext(ctx).
+		Model(&models.Store{}).
+		Scopes(activeMerchantStores)
+
 	order := "stores.id DESC"
-	if sortDir == "asc" {
+	switch sortDir {
+	case "asc":
 		order = "stores.id ASC"
+	case "popular":
+		query = query.Joins("LEFT JOIN (SELECT store_id, SUM(views) AS views FROM faq_view_counts GROUP BY store_id) AS popularity ON popularity.store_id = stores.id")
+		order = "COALESCE(popularity.views, 0) DESC, stores.id DESC"
 	}
 
 	var stores []models.Store
-	err := s.DB.WithContext(ctx).
-		Model(&models.Store{}).
+	err := query.
 		Order(order).
 		Limit(pageSize).
 		Offset((page - 1) * pageSize).
@@ -42,13 +75,22 @@ func (s *StoreService) ListStores(ctx context.Context, page, pageSize int, sortD
 
 	return stores, nil
 }
-func (s *StoreService) GetStoreWithFAQs(ctx context.Context, storeID uint, language string) (*models.Store, error) {
 
-	var store models.Store
-	if err := s.DB.WithContext(ctx).First(&store, storeID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrStoreNotFound
-		}
+// StoreFAQFilter narrows and orders the FAQs on a store's page.
+type StoreFAQFilter struct {
+	Search     string
+	Sort       string // "popular" orders by views in the store
+	CategoryID *uint  // includes the category's subcategories
+}
+
+// GetStoreWithFAQs loads a store with its live FAQs and the global ones,
+// grouped by category in the order the store chose, with unordered FAQs last
+// and newest first. With a search text only matching FAQs are returned, most
+// relevant first.
+func (s *StoreService) GetStoreWithFAQs(ctx context.Context, storeID uint, language string, filter StoreFAQFilter) (*models.Store, error) {
+
+	store, err := findActiveStore(s.DB.WithContext(ctx), storeID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -58,10 +100,41 @@ func (s *StoreService) GetStoreWithFAQs(ctx context.Context, storeID uint, langu
 
 	query := s.DB.WithContext(ctx).
 		Model(&models.FAQ{}).
-		Where("store_id = ? OR is_global = ?", storeID, true).
-		Preload("Category").
+		Where("faqs.store_id = ? OR faqs.is_global = ?", storeID, true).
+		Scopes(liveFAQs(time.Now())).
+		Preload("Category.Translations").
 		Preload("Translations").
-		Order("id DESC")
+		Joins("LEFT JOIN faq_positions AS positions ON positions.faq_id = faqs.id AND positions.store_id = ?", storeID)
+
+	var categoryIDs []uint
+	if filter.CategoryID != nil {
+		categoryIDs, err = categorySubtreeIDs(s.DB.WithContext(ctx), *filter.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if len(categoryIDs) == 0 {
+			return nil, ErrCategoryNotFound
+		}
+		query = query.Where("faqs.category_id IN ?", categoryIDs)
+	}
+
+	columns := "faqs.*, positions.position"
+	if filter.Sort == "popular" {
+		columns += ", COALESCE(popularity.views, 0) AS views"
+		query = joinStoreViews(query, storeID).Order("views DESC")
+	}
+	if filter.Search != "" {
+		hits, err := s.indexer.Search(ctx, search.Query{
+			Text:   filter.Search,
+			Filter: search.Filter{StoreIDs: []uint{storeID}, IncludeGlobal: true, CategoryIDs: categoryIDs},
+		})
+		if err != nil {
+			return nil, err
+		}
+		columns += ", hits.search_rank"
+		query = joinSearchHits(query, hits).Order("hits.search_rank DESC")
+	}
+	query = query.Select(columns).Order("faqs.category_id, positions.position NULLS LAST, faqs.id DESC")
 
 	if err := query.Find(&store.FAQs).Error; err != nil {
 		return nil, err
@@ -70,9 +143,112 @@ func (s *StoreService) GetStoreWithFAQs(ctx context.Context, storeID uint, langu
 	// Apply translation fallback per FAQ: prefer requested language, then English, then first available
 	for i := range store.FAQs {
 		store.FAQs[i].Translations = filterTranslationsWithFallback(store.FAQs[i].Translations, language)
+		localizeFAQCategory(&store.FAQs[i], language)
 	}
 
-	return &store, nil
+	return store, nil
+}
+
+// SuggestFAQs returns up to limit live questions from the store and the global
+// FAQs that resemble q, most similar first, using trigram word similarity so
+// prefixes and misspellings still match. Each FAQ appears once, in the
+// translation that matches best (preferring language on ties).
+func (s *StoreService) SuggestFAQs(ctx context.Context, storeID uint, q, language string, limit int) ([]FAQSuggestion, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, ErrSearchQueryRequired
+	}
+	if limit <= 0 || limit > maxSuggestions {
+		limit = 10
+	}
+
+	suggestions := []FAQSuggestion{}
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findActiveStore(tx, storeID); err != nil {
+			return err
+		}
+
+		// Applies to the <% operator for this transaction only.
+		threshold := strconv.FormatFloat(suggestThreshold, 'f', -1, 64)
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", threshold).Error; err != nil {
+			return err
+		}
+
+		faqIDs := tx.Model(&models.FAQ{}).
+			Select("id").
+			Where("store_id = ? OR is_global = ?", storeID, true).
+			Scopes(liveFAQs(time.Now()))
+
+		return tx.Raw(`SELECT faq_id, language, question, similarity FROM (
+			SELECT DISTINCT ON (faq_id) faq_id, language, question, word_similarity(@q, question) AS similarity
+			FROM translations
+			WHERE deleted_at IS NULL AND faq_id IN (@faqs) AND @q <% question
+			ORDER BY faq_id, similarity DESC, language = @language DESC
+		) AS best
+		ORDER BY similarity DESC, faq_id
+		LIMIT @limit`,
+			sql.Named("q", q),
+			sql.Named("faqs", faqIDs),
+			sql.Named("language", language),
+			sql.Named("limit", limit),
+		).Scan(&suggestions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// RecordView counts a customer opening an FAQ on a store's page. The view is
+// buffered and written later.
+func (s *StoreService) RecordView(storeID, faqID uint) {
+	s.views.Record(faqID, storeID)
+}
+
+// TopFAQs returns the store's most viewed live FAQs, its own and global ones,
+// optionally within one category and its subcategories.
+func (s *StoreService) TopFAQs(ctx context.Context, storeID uint, categoryID *uint, language string, limit int) ([]models.FAQ, error) {
+	if _, err := findActiveStore(s.DB.WithContext(ctx), storeID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxTopFAQs {
+		limit = 10
+	}
+
+	query := s.DB.WithContext(ctx).
+		Model(&models.FAQ{}).
+		Where("faqs.store_id = ? OR faqs.is_global = ?", storeID, true).
+		Scopes(liveFAQs(time.Now())).
+		Preload("Category.Translations").
+		Preload("Translations")
+	if categoryID != nil {
+		categoryIDs, err := categorySubtreeIDs(s.DB.WithContext(ctx), *categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if len(categoryIDs) == 0 {
+			return nil, ErrCategoryNotFound
+		}
+		query = query.Where("faqs.category_id IN ?", categoryIDs)
+	}
+
+	faqs := []models.FAQ{}
+	err := joinStoreViews(query, storeID).
+		Select("faqs.*, COALESCE(popularity.views, 0) AS views").
+		Order("views DESC, faqs.id DESC").
+		Limit(limit).
+		Find(&faqs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range faqs {
+		faqs[i].Translations = filterTranslationsWithFallback(faqs[i].Translations, language)
+		localizeFAQCategory(&faqs[i], language)
+	}
+
+	return faqs, nil
 }
 
 func (s *StoreService) GetStoreByID(ctx context.Context, id uint) (*models.Store, error) {
@@ -95,6 +271,30 @@ func (s *StoreService) GetStoreByMerchantID(merchantID uint) (*models.Store, err
 	return &store, nil
 }
 
+// joinStoreViews joins each FAQ's views in a store as "popularity".
+func joinStoreViews(db *gorm.DB, storeID uint) *gorm.DB {
+	return db.Joins("LEFT JOIN faq_view_counts AS popularity ON popularity.faq_id = faqs.id AND popularity.store_id = ?", storeID)
+}
+
+// findActiveStore loads a store that is visible on the public endpoints.
+func findActiveStore(db *gorm.DB, storeID uint) (*models.Store, error) {
+	var store models.Store
+	if err := db.Scopes(activeMerchantStores).First(&store, storeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStoreNotFound
+		}
+		return nil, err
+	}
+	return &store, nil
+}
+
+// activeMerchantStores limits a store query to stores whose owner is an active
+// merchant, so stores of suspended, deleted or demoted merchants disappear
+// from the public endpoints together with their FAQs.
+func activeMerchantStores(db *gorm.DB) *gorm.DB {
+	return db.Where("stores.merchant_id IN (SELECT id FROM users WHERE role = ? AND suspended_at IS NULL AND deleted_at IS NULL)", types.RoleMerchant)
+}
+
 // filterTranslationsWithFallback returns a single translation slice honoring the requested language,
 // then English, then any available translation.
 func filterTranslationsWithFallback(translations []models.Translation, language string) []models.Translation {
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kareemhamed001/faq/internal/models"
+	"github.com/kareemhamed001/faq/internal/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTrashType  = errors.New("invalid trash type")
+	ErrTrashItemNotFound = errors.New("item not found in trash")
+	ErrParentDeleted     = errors.New("restore the owning item first")
+)
+
+// TrashItem is a soft-deleted row as shown in the admin trash listing.
+type TrashItem struct {
+	Type      types.TrashType `json:"type"`
+	ID        uint            `json:"id"`
+	Label     string          `json:"label"`
+	DeletedAt time.Time       `json:"deleted_at"`
+	PurgeAt   *time.Time      `json:"purge_at,omitempty"`
+}
+
+// TrashService lists, restores and purges soft-deleted users, stores,
+// categories and FAQs. Rows deleted together share one deleted_at timestamp,
+// which is how a restore knows what belongs to the same deletion.
+type TrashService struct {
+	DB        *gorm.DB
+	retention time.Duration
+	indexer   *FAQIndexer
+}
+
+func NewTrashService(DB *gorm.DB, retention time.Duration, indexer *FAQIndexer) *TrashService {
+	return &TrashService{DB: DB, retention: retention, indexer: indexer}
+}
+
+func (s *TrashService) ListTrash(ctx context.Context, trashType types.TrashType, page, pageSize int, sortDir string) ([]TrashItem, int64, error) {
+	query := s.DB.WithContext(ctx).Where("deleted_at IS NOT NULL")
+
+	switch trashType {
+	case types.TrashUsers:
+		query = query.Table("users").Select("id, email AS label, deleted_at")
+	case types.TrashStores:
+		query = query.Table("stores").Select("id, name AS label, deleted_at")
+	case types.TrashCategories:
+		query = query.Table("categories").Select("id, name AS label, deleted_at")
+	case types.TrashFAQs:
+		query = query.Table("faqs").Select(`id, deleted_at, (
+			SELECT t.question FROM translations t
+			WHERE t.faq_id = faqs.id AND t.deleted_at = faqs.deleted_at
+			ORDER BY t.language = 'en' DESC, t.id
+			LIMIT 1
+		) AS label`)
+	default:
+		return nil, 0, ErrInvalidTrashType
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	order := "deleted_at DESC"
+	if sortDir == "asc" {
+		order = "deleted_at ASC"
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []TrashItem
+	err := query.Order(order).
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range items {
+		items[i].Type = trashType
+		if s.retention > 0 {
+			purgeAt := items[i].DeletedAt.Add(s.retention)
+			items[i].PurgeAt = &purgeAt
+		}
+	}
+
+	return items, total, nil
+}
+
+// Restore brings a trashed item back together with everything that was
+// deleted along with it. Items whose owner is still in the trash cannot be
+// restored on their own.
+//
+// FAQs deleted together with their store or owner stay in the search index, so
+// only a restored FAQ needs to be indexed again.
+func (s *TrashService) Restore(ctx context.Context, adminID uint, trashType types.TrashType, id uint) error {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		switch trashType {
+		case types.TrashUsers:
+			var user models.User
+			if err := findTrashed(tx, &user, id); err != nil {
+				return err
+			}
+			if err := restoreStoresOf(tx, "merchant_id = ?", user.ID, user.DeletedAt.Time); err != nil {
+				return err
+			}
+			return tx.Unscoped().Model(&user).Update("deleted_at", nil).Error
+
+		case types.TrashStores:
+			var store models.Store
+			if err := findTrashed(tx, &store, id); err != nil {
+				return err
+			}
+			if err := assertLive(tx, &models.User{}, store.MerchantID); err != nil {
+				return err
+			}
+			return restoreStoresOf(tx, "id = ?", store.ID, store.DeletedAt.Time)
+
+		case types.TrashCategories:
+			var category models.Category
+			if err := findTrashed(tx, &category, id); err != nil {
+				return err
+			}
+			if category.ParentID != nil {
+				if err := assertLive(tx, &models.Category{}, *category.ParentID); err != nil {
+					return err
+				}
+			}
+			return tx.Unscoped().Model(&category).Update("deleted_at", nil).Error
+
+		case types.TrashFAQs:
+			var faq models.FAQ
+			if err := findTrashed(tx, &faq, id); err != nil {
+				return err
+			}
+			if err := assertLive(tx, &models.Category{}, faq.CategoryID); err != nil {
+				return err
+			}
+			if faq.StoreID != nil {
+				if err := assertLive(tx, &models.Store{}, *faq.StoreID); err != nil {
+					return err
+				}
+			}
+			if err := restoreFAQsWhere(tx, "faqs.id = ?", faq.ID, faq.DeletedAt.Time); err != nil {
+				return err
+			}
+			return recordFAQRevision(tx, faq.ID, adminID, types.FAQRevisionRestored, nil)
+
+		default:
+			return ErrInvalidTrashType
+		}
+	})
+	if err != nil || trashType != types.TrashFAQs {
+		return err
+	}
+
+	s.indexer.Sync(ctx, id)
+	return nil
+}
+
+// Purge permanently removes everything deleted before the cutoff and returns
+// the number of rows removed. Rows still referenced by other rows are kept
+// until their dependants are purged.
+func (s *TrashService) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	var purgedFAQs []uint
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.FAQ{}).Where("deleted_at < ?", before).Pluck("id", &purgedFAQs).Error; err != nil {
+			return err
+		}
+
+		steps := []struct {
+			model interface{}
+			where string
+		}{
+			{&models.Translation{}, "deleted_at < ?"},
+			{&models.FAQ{}, "deleted_at < ?"},
+			{&models.Store{}, "deleted_at < ? AND NOT EXISTS (SELECT 1 FROM faqs WHERE faqs.store_id = stores.id)"},
+			{&models.User{}, "deleted_at < ? AND NOT EXISTS (SELECT 1 FROM stores WHERE stores.merchant_id = users.id)"},
+			{&models.Category{}, "deleted_at < ? AND NOT EXISTS (SELECT 1 FROM faqs WHERE faqs.category_id = categories.id) AND NOT EXISTS (SELECT 1 FROM categories children WHERE children.parent_id = categories.id)"},
+		}
+
+		for _, step := range steps {
+			result := tx.Unscoped().Where(step.where, before).Delete(step.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.indexer.Remove(ctx, purgedFAQs...)
+
+	return purged, nil
+}
+
+// StartPurging purges items older than the retention period every interval
+// until stop is closed. A zero retention or interval keeps the trash forever.
+func (s *TrashService) StartPurging(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	if s.retention <= 0 || interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := s.Purge(context.Background(), time.Now().Add(-s.retention)); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// softDeleteUser soft-deletes a user together with their store and its FAQs.
+func softDeleteUser(tx *gorm.DB, userID uint) error {
+	now := time.Now()
+
+	storeIDs := tx.Model(&models.Store{}).Select("id").Where("merchant_id = ?", userID)
+	if err := softDeleteFAQsWhere(tx, now, "store_id IN (?)", storeIDs); err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Store{}).Where("merchant_id = ?", userID).Update("deleted_at", now).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.User{}).Where("id = ?", userID).Update("deleted_at", now).Error
+}
+
+// softDeleteFAQsWhere soft-deletes the matching FAQs and their translations
+// with a single timestamp.
+func softDeleteFAQsWhere(tx *gorm.DB, now time.Time, query string, args ...interface{}) error {
+	faqIDs := tx.Model(&models.FAQ{}).Select("id").Where(query, args...)
+	if err := tx.Model(&models.Translation{}).Where("faq_id IN (?)", faqIDs).Update("deleted_at", now).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.FAQ{}).Where(query, args...).Update("deleted_at", now).Error
+}
+
+// restoreStoresOf restores the matching stores and the FAQs deleted with them.
+func restoreStoresOf(tx *gorm.DB, query string, arg uint, deletedAt time.Time) error {
+	storeIDs := tx.Unscoped().Model(&models.Store{}).Select("id").Where(query, arg).Where("deleted_at = ?", deletedAt)
+	if err := restoreFAQsWhere(tx, "faqs.store_id IN (?)", storeIDs, deletedAt); err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&models.Store{}).
+		Where(query, arg).
+		Where("deleted_at = ?", deletedAt).
+		Update("deleted_at", nil).Error
+}
+
+// restoreFAQsWhere restores the matching FAQs deleted at deletedAt, along with
+// the translations that were deleted with them. Translations removed earlier
+// by an edit stay deleted.
+func restoreFAQsWhere(tx *gorm.DB, query string, arg interface{}, deletedAt time.Time) error {
+	faqIDs := tx.Unscoped().Model(&models.FAQ{}).Select("id").Where(query, arg).Where("deleted_at = ?", deletedAt)
+	if err := tx.Unscoped().Model(&models.Translation{}).
+		Where("faq_id IN (?) AND deleted_at = ?", faqIDs, deletedAt).
+		Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&models.FAQ{}).
+		Where(query, arg).
+		Where("deleted_at = ?", deletedAt).
+		Update("deleted_at", nil).Error
+}
+
+func findTrashed(tx *gorm.DB, dest interface{}, id uint) error {
+	err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(dest, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTrashItemNotFound
+	}
+	return err
+}
+
+func assertLive(tx *gorm.DB, model interface{}, id uint) error {
+	var count int64
+	if err := tx.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrParentDeleted
+	}
+	return nil
+}
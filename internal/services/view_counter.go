@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const viewFlushBatchSize = 500
+
+type viewKey struct {
+	FAQID   uint
+	StoreID uint
+}
+
+// ViewCounter counts FAQ views per store in memory and adds them to
+// faq_view_counts in batches, so recording a view never waits on the
+// database. Views of FAQs or stores that do not exist are dropped on flush.
+type ViewCounter struct {
+	DB         *gorm.DB
+	maxPending int
+
+	mu      sync.Mutex
+	pending map[viewKey]int64
+}
+
+// NewViewCounter keeps at most maxPending distinct FAQ and store pairs between
+// flushes; views of further pairs are dropped until the next flush.
+func NewViewCounter(DB *gorm.DB, maxPending int) *ViewCounter {
+	return &ViewCounter{DB: DB, maxPending: maxPending, pending: make(map[viewKey]int64)}
+}
+
+func (c *ViewCounter) Record(faqID, storeID uint) {
+	c.add(viewKey{FAQID: faqID, StoreID: storeID}, 1)
+}
+
+// Flush writes the buffered views. Views that could not be written are kept
+// for the next flush.
+func (c *ViewCounter) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[viewKey]int64)
+	c.mu.Unlock()
+
+	keys := make([]viewKey, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+
+	for start := 0; start < len(keys); start += viewFlushBatchSize {
+		end := start + viewFlushBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := c.write(ctx, keys[start:end], pending); err != nil {
+			for _, key := range keys[start:] {
+				c.add(key, pending[key])
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start flushes every interval until stop is closed, and once more on the way
+// out. The returned channel is closed after that last flush.
+func (c *ViewCounter) Start(interval time.Duration, stop <-chan struct{}, onError func(error)) <-chan struct{} {
+	done := make(chan struct{})
+	if interval <= 0 {
+		close(done)
+		return done
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				if err := c.Flush(context.Background()); err != nil && onError != nil {
+					onError(err)
+				}
+				return
+			case <-ticker.C:
+				if err := c.Flush(context.Background()); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+	return done
+}
+
+func (c *ViewCounter) add(key viewKey, views int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pending[key]; !ok && len(c.pending) >= c.maxPending {
+		return
+	}
+	c.pending[key] += views
+}
+
+func (c *ViewCounter) write(ctx context.Context, keys []viewKey, views map[viewKey]int64) error {
+	rows := make([]string, len(keys))
+	args := make([]interface{}, 0, 3*len(keys))
+	for i, key := range keys {
+		rows[i] = "(?::int, ?::int, ?::bigint)"
+		args = append(args, key.FAQID, key.StoreID, views[key])
+	}
+
+	// Views of FAQs or stores deleted since, and of FAQs that belong to
+	// another store, are dropped.
+	return c.DB.WithContext(ctx).Exec(`INSERT INTO faq_view_counts (faq_id, store_id, views)
+		SELECT v.faq_id, v.store_id, v.views
+		FROM (VALUES `+strings.Join(rows, ", ")+`) AS v(faq_id, store_id, views)
+		JOIN faqs ON faqs.id = v.faq_id AND (faqs.store_id = v.store_id OR faqs.is_global)
+		JOIN stores ON stores.id = v.store_id
+		ON CONFLICT (faq_id, store_id) DO UPDATE SET views = faq_view_counts.views + EXCLUDED.views`, args...).Error
+}
@@ -0,0 +1,8 @@
+package types
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead      APIKeyScope = "read"
+	APIKeyScopeReadWrite APIKeyScope = "read_write"
+)
@@ -0,0 +1,10 @@
+package types
+
+type FAQRevisionAction string
+
+const (
+	FAQRevisionCreated  FAQRevisionAction = "created"
+	FAQRevisionUpdated  FAQRevisionAction = "updated"
+	FAQRevisionDeleted  FAQRevisionAction = "deleted"
+	FAQRevisionRestored FAQRevisionAction = "restored"
+)
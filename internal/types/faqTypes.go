@@ -0,0 +1,30 @@
+package types
+
+type FAQStatus string
+
+const (
+	FAQStatusDraft     FAQStatus = "draft"
+	FAQStatusInReview  FAQStatus = "in_review"
+	FAQStatusPublished FAQStatus = "published"
+	FAQStatusArchived  FAQStatus = "archived"
+)
+
+type FAQTransition string
+
+const (
+	FAQTransitionSubmit    FAQTransition = "submit"
+	FAQTransitionApprove   FAQTransition = "approve"
+	FAQTransitionReject    FAQTransition = "reject"
+	FAQTransitionArchive   FAQTransition = "archive"
+	FAQTransitionUnarchive FAQTransition = "unarchive"
+)
+
+// FAQFileFormat is a file format supported by FAQ import and export.
+type FAQFileFormat string
+
+const (
+	FAQFileCSV  FAQFileFormat = "csv"
+	FAQFileJSON FAQFileFormat = "json"
+	FAQFileYAML FAQFileFormat = "yaml"
+	FAQFileXLSX FAQFileFormat = "xlsx"
+)
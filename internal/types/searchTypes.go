@@ -0,0 +1,11 @@
+package types
+
+// SearchSource is the endpoint a recorded search came through.
+type SearchSource string
+
+const (
+	SearchSourceFAQs    SearchSource = "faqs"
+	SearchSourceStore   SearchSource = "store"
+	SearchSourceAsk     SearchSource = "ask"
+	SearchSourceSuggest SearchSource = "suggest"
+)
@@ -0,0 +1,10 @@
+package types
+
+type TrashType string
+
+const (
+	TrashUsers      TrashType = "users"
+	TrashStores     TrashType = "stores"
+	TrashCategories TrashType = "categories"
+	TrashFAQs       TrashType = "faqs"
+)